@@ -0,0 +1,47 @@
+package links
+
+import (
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ygrebnov/links/internal"
+)
+
+var (
+	// checkFlags are check command flags.
+	checkFlags = slices.Concat(commonFlags, []flag{
+		{
+			name:      "file",
+			shorthand: "f",
+			value:     "",
+			usage: `path to a file with URLs to check, one per line.
+Use '-' to read URLs from stdin (default if no URLs given)`,
+		},
+	})
+
+	checkCmd = &cobra.Command{
+		Use:   "check [url...]",
+		Short: "Check a list of URLs without discovering links",
+		Long: `Check the given URLs, URLs read from a file or stdin, one per line.
+Responses are not parsed, so no further links are discovered.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			return bindFlags(cmd, checkFlags)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// usage is not relevant for checking errors.
+			cmd.SilenceUsage = true
+
+			file, err := cmd.Flags().GetString("file")
+			if err != nil {
+				return err
+			}
+
+			return internal.Check(cfgFile, args, file)
+		},
+	}
+)
+
+func initCheckCmd() {
+	addFlags(checkCmd, checkFlags)
+}
@@ -0,0 +1,137 @@
+package links
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// flag is a command line flag overriding the configuration key value.
+// Flag type is defined by its default value type. Flags without key are read by the command itself.
+type flag struct {
+	name      string
+	shorthand string
+	key       string
+	value     any
+	usage     string
+}
+
+// commonFlags are flags shared by inspect and check commands.
+var commonFlags = []flag{
+	{
+		name:  "host",
+		key:   "inspector.host",
+		value: "",
+		usage: "host address, relative URLs are resolved against. May be omitted, if absolute URLs are given",
+	},
+	{
+		name:  "skipok",
+		key:   "printer.skipOk",
+		value: false,
+		usage: "do not output links checks returning 200 status code",
+	},
+	{
+		name:      "out",
+		shorthand: "o",
+		key:       "printer.outputFormat",
+		value:     "stdout",
+		usage:     "output format. Possible values are: stdout (default), html, csv, json, ndjson, junit",
+	},
+	{
+		name:  "output-path",
+		key:   "printer.outputPath",
+		value: "",
+		usage: `file report path or directory. May contain {host} and {timestamp} placeholders.
+Use '-' to output file report to stdout (default: temporary directory)`,
+	},
+	{
+		name:  "fail-on",
+		key:   "inspector.failOn",
+		value: []string(nil),
+		usage: `fail inspection if links with matching statuses are found.
+Possible values are: any (all broken links), status codes classes (4xx, 5xx), status codes (404), statuses (ERR)`,
+	},
+	{
+		name:  "fail-threshold",
+		key:   "inspector.failThreshold",
+		value: 0,
+		usage: "maximum number of links matching --fail-on criteria not failing inspection",
+	},
+	{
+		name:  "concurrency",
+		key:   "inspector.concurrency",
+		value: uint(0),
+		usage: "maximum number of concurrent requests to the inspected host (default: number of CPUs)",
+	},
+	{
+		name:  "requests-per-second",
+		key:   "inspector.requestsPerSecond",
+		value: float64(0),
+		usage: "maximum number of requests per second to each host. Zero means no limit",
+	},
+	{
+		name:  "cookies-file",
+		key:   "inspector.cookiesFile",
+		value: "",
+		usage: "path to a Netscape format cookies file. Cookies are sent in requests to the inspected host",
+	},
+	{
+		name:  "resolve",
+		key:   "inspector.resolve",
+		value: []string(nil),
+		usage: `connect to the given address instead of resolving the host, in the host:port:address format.
+For example, example.com:443:10.0.0.1`,
+	},
+	{
+		name:  "dir",
+		key:   "inspector.dir",
+		value: "",
+		usage: `static site build directory read offline instead of requesting the host.
+Host defaults to http://localhost`,
+	},
+}
+
+// addFlags registers the given flags on the command.
+func addFlags(cmd *cobra.Command, flags []flag) {
+	for _, f := range flags {
+		switch v := f.value.(type) {
+		case string:
+			cmd.Flags().StringP(f.name, f.shorthand, v, f.usage)
+
+		case bool:
+			cmd.Flags().BoolP(f.name, f.shorthand, v, f.usage)
+
+		case int:
+			cmd.Flags().IntP(f.name, f.shorthand, v, f.usage)
+
+		case uint:
+			cmd.Flags().UintP(f.name, f.shorthand, v, f.usage)
+
+		case float64:
+			cmd.Flags().Float64P(f.name, f.shorthand, v, f.usage)
+
+		case []string:
+			cmd.Flags().StringSliceP(f.name, f.shorthand, v, f.usage)
+
+		default:
+			panic(fmt.Sprintf("unsupported flag %s value type %T", f.name, v))
+		}
+	}
+}
+
+// bindFlags binds the given command flags to their configuration keys.
+// Flags are bound on the command execution, so that commands may share configuration keys.
+func bindFlags(cmd *cobra.Command, flags []flag) error {
+	for _, f := range flags {
+		if f.key == "" {
+			continue
+		}
+
+		if err := viper.BindPFlag(f.key, cmd.Flags().Lookup(f.name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
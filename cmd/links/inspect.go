@@ -1,76 +1,105 @@
 package links
 
 import (
+	"slices"
+
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 
 	"github.com/ygrebnov/links/internal"
 )
 
 var (
-	outputFormat string
+	// inspectFlags are inspect command flags.
+	inspectFlags = slices.Concat(commonFlags, []flag{
+		{
+			name:  "ignore-robots",
+			key:   "inspector.ignoreRobotsTxt",
+			value: false,
+			usage: "do not respect robots.txt rules and crawl delay",
+		},
+		{
+			name:  "sitemap",
+			key:   "inspector.sitemap",
+			value: "",
+			usage: "sitemap URL or path to seed inspection from. Use 'auto' to discover sitemaps from robots.txt",
+		},
+		{
+			name:  "max-depth",
+			key:   "inspector.maxDepth",
+			value: uint(0),
+			usage: "maximum number of links to follow from the start page. Zero means no limit",
+		},
+		{
+			name:  "max-pages",
+			key:   "inspector.maxPages",
+			value: uint(0),
+			usage: "maximum number of requested pages. Zero means no limit",
+		},
+		{
+			name:  "include",
+			key:   "inspector.include",
+			value: []string(nil),
+			usage: `inspect only URLs with path and query matching the given patterns.
+Patterns are globs, like /docs/**, or regular expressions prefixed with 're:'`,
+		},
+		{
+			name:  "exclude",
+			key:   "inspector.exclude",
+			value: []string(nil),
+			usage: "do not inspect URLs with path and query matching the given patterns",
+		},
+		{
+			name:  "parser-concurrency",
+			key:   "inspector.parserConcurrency",
+			value: uint(0),
+			usage: "maximum number of concurrently parsed pages (default: number of CPUs)",
+		},
+		{
+			name:  "internal-hosts",
+			key:   "inspector.internalHosts",
+			value: []string(nil),
+			usage: `hosts inspected along with the --host one. Wildcards, like *.example.com, match any subdomain`,
+		},
+		{
+			name:  "path",
+			value: []string(nil),
+			usage: "start path, can be repeated (default: '/')",
+		},
+		{
+			name:  "url",
+			key:   "inspector.startURLs",
+			value: []string(nil),
+			usage: `start URL, can be repeated. URLs on different hosts are inspected in one run,
+results are grouped per site`,
+		},
+		{
+			name:  "urls-file",
+			key:   "inspector.startURLsFile",
+			value: "",
+			usage: "path to a file with start URLs, one per line",
+		},
+	})
 
 	inspectCmd = &cobra.Command{
 		Use:   "inspect",
 		Short: "Discover and check links",
-		RunE: func(_ *cobra.Command, _ []string) error {
-			return internal.Inspect(cfgFile, start)
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			return bindFlags(cmd, inspectFlags)
 		},
-	}
-)
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			// usage is not relevant for inspection errors.
+			cmd.SilenceUsage = true
 
-func initInspectCmd() error {
-	inspectCmd.
-		Flags().
-		StringVar(
-			&host,
-			"host",
-			"",
-			"host address",
-		)
-	if err := inspectCmd.MarkFlagRequired("host"); err != nil {
-		return err
-	}
+			start, err := cmd.Flags().GetStringSlice("path")
+			if err != nil {
+				return err
+			}
 
-	if err := viper.BindPFlag("inspector.host", inspectCmd.Flags().Lookup("host")); err != nil {
-		return err
-	}
-
-	inspectCmd.
-		Flags().
-		BoolVar(
-			&skipOK,
-			"skipok",
-			false,
-			"do not output links checks returning 200 status code",
-		)
-
-	if err := viper.BindPFlag("printer.skipOk", inspectCmd.Flags().Lookup("skipok")); err != nil {
-		return err
-	}
-
-	inspectCmd.
-		Flags().
-		StringVarP(
-			&outputFormat,
-			"out",
-			"o",
-			"stdout",
-			"output format. Possible values are: stdout (default), html, csv",
-		)
-
-	if err := viper.BindPFlag("printer.outputFormat", inspectCmd.Flags().Lookup("out")); err != nil {
-		return err
+			return internal.Inspect(cfgFile, start)
+		},
 	}
+)
 
-	inspectCmd.
-		Flags().
-		StringVar(
-			&start,
-			"path",
-			"/",
-			"start path (default: '/')",
-		)
-
-	return nil
+func initInspectCmd() {
+	addFlags(inspectCmd, inspectFlags)
 }
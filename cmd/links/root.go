@@ -6,9 +6,7 @@ import (
 
 var (
 	// Used for flags.
-	cfgFile     string
-	host, start string
-	skipOK      bool
+	cfgFile string
 
 	rootCmd = &cobra.Command{
 		Use:               "links",
@@ -40,8 +38,9 @@ of 'links config show' command output).
 Config file is automatically created only on 'config set' command execution.`,
 		)
 
-	cobra.CheckErr(initInspectCmd())
+	initInspectCmd()
+	initCheckCmd()
 	initConfigCmd()
 
-	rootCmd.AddCommand(inspectCmd, configCmd, versionCmd)
+	rootCmd.AddCommand(inspectCmd, checkCmd, configCmd, versionCmd)
 }
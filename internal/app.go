@@ -3,25 +3,72 @@ package internal
 import (
 	"context"
 	"fmt"
-	"net"
-	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"slices"
 	"sync"
 	"syscall"
-	"time"
 )
 
 const applicationName = "links"
 
 var version, buildTime string
 
-func Inspect(cfgFile, startURL string) error {
+// Inspect inspects the sites starting from the given paths, relative to the inspected host,
+// and inspector.startURLs. The inspected host root is the start page, if none of them are given.
+func Inspect(cfgFile string, startPaths []string) error {
 	cfg, cfgErr := newConfig(cfgFile, injectables{})
 	if cfgErr != nil {
 		return fmt.Errorf("cannot load configuration: %w", cfgErr)
 	}
 
+	cfg.Inspector.StartURLs = slices.Concat(startPaths, cfg.Inspector.StartURLs)
+
+	return run(cfg, func(ctx context.Context, i inspector, done chan<- struct{}) {
+		i.inspect(ctx, cfg.Inspector.StartURLs, done)
+	})
+}
+
+// Check checks the given URLs and URLs read from the given file or stdin without discovering further links.
+// URLs may be relative to the inspected host, which defaults to the first URL origin.
+func Check(cfgFile string, urls []string, urlsFile string) error {
+	urls, err := readCheckURLs(urls, urlsFile, os.Stdin)
+	if err != nil {
+		return fmt.Errorf("cannot read URLs: %w", err)
+	}
+
+	if len(urls) == 0 {
+		return ErrNoURLsToCheck
+	}
+
+	cfg, cfgErr := newConfig(cfgFile, injectables{})
+	if cfgErr != nil && !isEmptyHostError(cfgErr) {
+		// single ErrEmptyHostValue is skipped as the inspected host is not required for 'check' command.
+		return fmt.Errorf("cannot load configuration: %w", cfgErr)
+	}
+
+	if cfg.Inspector.Host == "" {
+		u, err := url.Parse(urls[0])
+		if err != nil || !u.IsAbs() {
+			return fmt.Errorf("cannot load configuration: %w", cfgErr)
+		}
+
+		// inspected host session must not be sent to whatever URL comes first.
+		if cfg.Inspector.hasHostSession() {
+			return ErrCheckHostRequired
+		}
+
+		cfg.Inspector.Host = getOrigin(u)
+	}
+
+	return run(cfg, func(ctx context.Context, i inspector, done chan<- struct{}) {
+		i.check(ctx, urls, done)
+	})
+}
+
+// run initializes the inspector and printer, runs the given inspection and checks its failure criteria.
+func run(cfg *config, inspect func(ctx context.Context, i inspector, done chan<- struct{})) error {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
@@ -31,23 +78,32 @@ func Inspect(cfgFile, startURL string) error {
 
 	data := &sync.Map{}
 
-	dialer := &net.Dialer{
-		Timeout:   30 * time.Second,
-		KeepAlive: 30 * time.Second,
+	if cfg.Inspector.TLS != nil && cfg.Inspector.TLS.InsecureSkipVerify {
+		_, _ = fmt.Fprintln(
+			os.Stderr,
+			"WARNING: TLS certificates verification is disabled by inspector.tls.insecureSkipVerify.",
+			"Connections are vulnerable to man-in-the-middle attacks and certificate problems are not reported.",
+		)
+	}
+
+	httpClient, err := newHTTPClient(cfg.Inspector, false)
+	if err != nil {
+		return fmt.Errorf("cannot initialize http client: %w", err)
+	}
+
+	externalHTTPClient, err := newHTTPClient(cfg.Inspector, true)
+	if err != nil {
+		return fmt.Errorf("cannot initialize http client: %w", err)
 	}
-	tr := &http.Transport{
-		Proxy:                 http.ProxyFromEnvironment,
-		DialContext:           dialer.DialContext,
-		ForceAttemptHTTP2:     true,
-		MaxIdleConnsPerHost:   1024,
-		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   10 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
+
+	if err = login(ctx, httpClient, cfg.Inspector); err != nil {
+		return fmt.Errorf("cannot log in: %w", err)
 	}
 
 	i, err := newInspector(
 		cfg.Inspector,
-		&http.Client{Timeout: cfg.Inspector.RequestTimeout, Transport: tr},
+		httpClient,
+		externalHTTPClient,
 		data,
 		toPrint,
 		injectables{},
@@ -56,13 +112,13 @@ func Inspect(cfgFile, startURL string) error {
 		return fmt.Errorf("cannot initialize inspector: %w", err)
 	}
 
-	newPrinter(&cfg.Printer, injectables{}, data).run(ctx, toPrint, doneInspecting, donePrinting)
+	newPrinter(&cfg.Printer, cfg.Inspector, injectables{}, data).run(ctx, toPrint, doneInspecting, donePrinting)
 
-	i.inspect(ctx, startURL, doneInspecting)
+	inspect(ctx, i, doneInspecting)
 
 	<-donePrinting
 
-	return nil
+	return checkFailure(cfg.Inspector, data)
 }
 
 func ShowConfig(cfgFile, o string) error {
@@ -0,0 +1,161 @@
+package internal
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/ygrebnov/errorc"
+)
+
+// Secret value prefixes.
+const (
+	secretPrefixEnv  = "env:"
+	secretPrefixFile = "file:"
+)
+
+// hostAuth holds resolved credentials and headers sent in requests to a host.
+type hostAuth struct {
+	scheme      string
+	host        string
+	username    string
+	password    string
+	bearerToken string
+	headers     http.Header
+}
+
+// newHostAuths resolves credentials and headers configured in inspector.auth.
+// Entries without host are applied to the inspected host.
+// Credentials are sent over the inspected host scheme only, so that HTTPS credentials never go in cleartext.
+func newHostAuths(cfg *inspectorConfig) ([]*hostAuth, error) {
+	if len(cfg.Auth) == 0 {
+		return nil, nil
+	}
+
+	baseURL, err := url.Parse(cfg.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	auths := make([]*hostAuth, 0, len(cfg.Auth))
+	for _, c := range cfg.Auth {
+		a := &hostAuth{scheme: baseURL.Scheme, host: c.Host, headers: make(http.Header, len(c.Headers))}
+		if a.host == "" {
+			a.host = baseURL.Host
+		}
+
+		for _, v := range []struct {
+			value  string
+			target *string
+		}{
+			{c.Username, &a.username},
+			{c.Password, &a.password},
+			{c.BearerToken, &a.bearerToken},
+		} {
+			if *v.target, err = resolveSecret(v.value); err != nil {
+				return nil, err
+			}
+		}
+
+		for k, v := range c.Headers {
+			value, err := resolveSecret(v)
+			if err != nil {
+				return nil, err
+			}
+
+			a.headers.Set(k, value)
+		}
+
+		auths = append(auths, a)
+	}
+
+	return auths, nil
+}
+
+// matches checks whether the credentials are to be sent in requests to the given URL.
+// Scheme must match, host without port matches any port.
+func (a *hostAuth) matches(u *url.URL) bool {
+	if !strings.EqualFold(a.scheme, u.Scheme) {
+		return false
+	}
+
+	return strings.EqualFold(a.host, u.Host) || strings.EqualFold(a.host, u.Hostname())
+}
+
+// apply adds the credentials and headers to the given request.
+func (a *hostAuth) apply(req *http.Request) {
+	if a.username != "" || a.password != "" {
+		req.SetBasicAuth(a.username, a.password)
+	}
+
+	if a.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+a.bearerToken)
+	}
+
+	for k, v := range a.headers {
+		req.Header[k] = v
+	}
+}
+
+// resolveSecret returns the environment variable value or the file content for values
+// prefixed with "env:" and "file:" respectively. Other values are returned as is.
+func resolveSecret(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, secretPrefixEnv):
+		v, ok := os.LookupEnv(strings.TrimPrefix(value, secretPrefixEnv))
+		if !ok {
+			return "", errorc.With(
+				ErrCannotResolveSecretValue,
+				errorc.Field("value", value),
+				errorc.Field("reason", "environment variable is not set"),
+			)
+		}
+
+		return v, nil
+
+	case strings.HasPrefix(value, secretPrefixFile):
+		b, err := os.ReadFile(strings.TrimPrefix(value, secretPrefixFile))
+		if err != nil {
+			return "", errorc.With(
+				ErrCannotResolveSecretValue,
+				errorc.Field("value", value),
+				errorc.Field("reason", err.Error()),
+			)
+		}
+
+		return strings.TrimRight(string(b), "\r\n"), nil
+	}
+
+	return value, nil
+}
+
+// authTransport adds credentials and headers to requests to the hosts they are configured for.
+// As it is called for each redirect hop, credentials are never forwarded to other hosts.
+type authTransport struct {
+	base  http.RoundTripper
+	auths []*hostAuth
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var authReq *http.Request
+
+	for _, a := range t.auths {
+		if !a.matches(req.URL) {
+			continue
+		}
+
+		// request must not be modified by a round tripper.
+		if authReq == nil {
+			authReq = req.Clone(req.Context())
+		}
+
+		a.apply(authReq)
+	}
+
+	if authReq == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	return t.base.RoundTrip(authReq)
+}
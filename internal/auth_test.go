@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSecret(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "secret")
+	require.NoError(t, os.WriteFile(file, []byte("file secret\n"), 0o600))
+
+	t.Setenv("LINKS_TEST_SECRET", "env secret")
+
+	tests := []struct {
+		name        string
+		value       string
+		expected    string
+		expectedErr error
+	}{
+		{name: "plain", value: "secret", expected: "secret"},
+		{name: "empty", value: "", expected: ""},
+		{name: "env", value: "env:LINKS_TEST_SECRET", expected: "env secret"},
+		{name: "unset env", value: "env:LINKS_TEST_UNSET", expectedErr: ErrCannotResolveSecretValue},
+		{name: "file", value: "file:" + file, expected: "file secret"},
+		{name: "missing file", value: "file:" + filepath.Join(dir, "missing"), expectedErr: ErrCannotResolveSecretValue},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v, err := resolveSecret(test.value)
+			if test.expectedErr != nil {
+				require.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expected, v)
+		})
+	}
+}
+
+func TestHostAuth_Matches(t *testing.T) {
+	tests := []struct {
+		scheme   string
+		host     string
+		url      string
+		expected bool
+	}{
+		{"http", "example.com", "http://example.com/page", true},
+		{"https", "example.com", "https://EXAMPLE.com:8443/page", true},
+		{"http", "example.com:8080", "http://example.com:8080/page", true},
+		{"http", "example.com:8080", "http://example.com/page", false},
+		{"http", "example.com", "http://sub.example.com/page", false},
+		{"http", "example.com", "http://example.org/page", false},
+		{"https", "example.com", "http://example.com/page", false},
+		{"http", "example.com", "https://example.com/page", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scheme+" "+test.host+" "+test.url, func(t *testing.T) {
+			u, err := url.Parse(test.url)
+			require.NoError(t, err)
+
+			require.Equal(t, test.expected, (&hostAuth{scheme: test.scheme, host: test.host}).matches(u))
+		})
+	}
+}
+
+func TestNewHostAuths(t *testing.T) {
+	auths, err := newHostAuths(&inspectorConfig{
+		Host: "http://localhost:8080",
+		Auth: []authConfig{
+			{Username: "user", Password: "password"},
+			{Host: "api.localhost", Headers: map[string]string{"x-api-key": "key"}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		[]*hostAuth{
+			{scheme: "http", host: "localhost:8080", username: "user", password: "password", headers: http.Header{}},
+			{scheme: "http", host: "api.localhost", headers: http.Header{"X-Api-Key": {"key"}}},
+		},
+		auths,
+	)
+
+	_, err = newHostAuths(&inspectorConfig{
+		Host: "http://localhost",
+		Auth: []authConfig{{BearerToken: "env:LINKS_TEST_UNSET"}},
+	})
+	require.ErrorIs(t, err, ErrCannotResolveSecretValue)
+}
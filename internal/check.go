@@ -0,0 +1,126 @@
+package internal
+
+import (
+	"context"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/ygrebnov/workers"
+)
+
+// stdinPath is a URLs file path value standing for reading URLs from stdin.
+const stdinPath = "-"
+
+// readCheckURLs returns the given URLs followed by URLs read from the given file.
+// URLs are read from stdin, if the file path is "-" or if neither URLs nor file are given.
+func readCheckURLs(urls []string, path string, stdin io.Reader) ([]string, error) {
+	var (
+		read []string
+		err  error
+	)
+
+	switch {
+	case path == stdinPath || (path == "" && len(urls) == 0):
+		read, err = readURLs(stdin)
+
+	case path != "":
+		f, openErr := os.Open(path)
+		if openErr != nil {
+			return nil, openErr
+		}
+		defer func() { _ = f.Close() }()
+
+		read, err = readURLs(f)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Concat(urls, read), nil
+}
+
+// check requests the given URLs, which may be relative to the inspected host.
+// URLs fragments are ignored. Responses bodies are not parsed, so no further links are discovered.
+func (i *defaultInspector) check(ctx context.Context, urls []string, done chan<- struct{}) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	i.htmlProvider = workers.New[*link](
+		ctx,
+		&workers.Config{MaxWorkers: getMaxWorkers(i.cfg.Concurrency), StartImmediately: true},
+	)
+
+	go i.provideHTML(ctx, i.htmlProvider)
+
+	seen := make(map[string]struct{}, len(urls))
+	for _, href := range urls {
+		if u, err := i.baseURL.Parse(href); err == nil {
+			u.Fragment, u.RawFragment = "", ""
+			href = u.String()
+		}
+
+		// duplicate URLs are checked once.
+		if _, ok := seen[href]; ok {
+			continue
+		}
+		seen[href] = struct{}{}
+
+		i.wg.Add(1)
+		_ = i.htmlProvider.AddTask(i.newCheckTask(href))
+	}
+
+	i.wg.Wait()
+	cancel()
+
+	done <- struct{}{}
+}
+
+// newCheckTask creates a task checking the link at the given URL.
+// Links to hosts other than the internal ones are requested with the external client,
+// so that the session cookies are not sent to them. Credentials are sent only to the hosts they are configured for.
+func (i *defaultInspector) newCheckTask(href string) func(ctx context.Context) *link {
+	return func(ctx context.Context) *link {
+		u, err := i.baseURL.Parse(href)
+		if err != nil {
+			return i.store(&link{URL: href, code: statusError, err: err}, nil, "")
+		}
+
+		client := i.httpClient
+		if !i.isInternal(u) {
+			client = i.externalHTTPClient
+		}
+
+		l := i.get(ctx, client, u.String())
+		if l.body != nil {
+			_ = l.body.Close()
+			l.body = nil
+		}
+		l.resource = true // checked links are not inspected.
+		l.external = !i.isInternal(u)
+
+		return i.store(l, nil, "")
+	}
+}
+
+// hasHostSession checks whether credentials, cookies or login apply to the inspected host,
+// as inspector.auth entries without host, inspector.cookies entries without domain and inspector.login do.
+func (c *inspectorConfig) hasHostSession() bool {
+	if c.Login != nil {
+		return true
+	}
+
+	for _, a := range c.Auth {
+		if a.Host == "" {
+			return true
+		}
+	}
+
+	for _, cookie := range c.Cookies {
+		if cookie.Domain == "" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCheckURLs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "urls.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# redirects map\nhttp://host/old\n\n/new\n"), 0o600))
+
+	tests := []struct {
+		name     string
+		urls     []string
+		path     string
+		stdin    string
+		expected []string
+	}{
+		{name: "arguments", urls: []string{"http://host/a"}, stdin: "http://host/b", expected: []string{"http://host/a"}},
+		{
+			name:     "arguments and file",
+			urls:     []string{"http://host/a"},
+			path:     path,
+			expected: []string{"http://host/a", "http://host/old", "/new"},
+		},
+		{name: "stdin", stdin: "http://host/b\n# skipped\n", expected: []string{"http://host/b"}},
+		{name: "explicit stdin", urls: []string{"/a"}, path: "-", stdin: "/b", expected: []string{"/a", "/b"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			urls, err := readCheckURLs(test.urls, test.path, strings.NewReader(test.stdin))
+			require.NoError(t, err)
+			require.Equal(t, test.expected, urls)
+		})
+	}
+
+	_, err := readCheckURLs(nil, filepath.Join(t.TempDir(), "missing.txt"), strings.NewReader(""))
+	require.Error(t, err)
+}
+
+func TestInspector_Check(t *testing.T) {
+	httpClient := &mockHTTPClient{
+		data: map[string]*http.Response{
+			"http://host/start": {
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`<a href="link1">Link1</a>`)),
+			},
+		},
+		do: (*mockHTTPClient).defaultDo,
+	}
+
+	externalHTTPClient := &mockHTTPClient{
+		data: map[string]*http.Response{
+			"http://other/page": {StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`<a href="/">Home</a>`))},
+		},
+		do: (*mockHTTPClient).defaultDo,
+	}
+
+	visitedURLs := &sync.Map{}
+	toPrint := make(chan *link, 1024)
+	done := make(chan struct{}, 1)
+
+	i, err := newInspector(defaultConfig, httpClient, externalHTTPClient, visitedURLs, toPrint, injectables{})
+	require.NoError(t, err)
+
+	i.check(context.Background(), []string{"start", "http://host/start", "http://other/page#section", "missing"}, done)
+	<-done
+
+	expected := map[string]int{
+		"http://host/start":   http.StatusOK,
+		"http://other/page":   http.StatusOK,
+		"http://host/missing": http.StatusNotFound,
+	}
+
+	require.Len(t, toPrint, len(expected))
+
+	for u, code := range expected {
+		l, ok := visitedURLs.Load(u)
+		require.True(t, ok, u)
+		require.Equal(t, code, l.(*link).code, u)
+	}
+}
+
+func TestInspectorConfig_HasHostSession(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      *inspectorConfig
+		expected bool
+	}{
+		{name: "empty", cfg: &inspectorConfig{}, expected: false},
+		{name: "host auth", cfg: &inspectorConfig{Auth: []authConfig{{Username: "user"}}}, expected: true},
+		{
+			name:     "other host auth",
+			cfg:      &inspectorConfig{Auth: []authConfig{{Host: "api.example.com", Username: "user"}}},
+			expected: false,
+		},
+		{name: "host cookie", cfg: &inspectorConfig{Cookies: []cookieConfig{{Name: "session"}}}, expected: true},
+		{
+			name:     "domain cookie",
+			cfg:      &inspectorConfig{Cookies: []cookieConfig{{Name: "session", Domain: "example.com"}}},
+			expected: false,
+		},
+		{name: "login", cfg: &inspectorConfig{Login: &loginConfig{URL: "/login"}}, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, test.cfg.hasHostSession())
+		})
+	}
+}
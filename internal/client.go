@@ -0,0 +1,176 @@
+package internal
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ygrebnov/errorc"
+)
+
+// maxRedirects is the maximum number of redirects followed for a single request.
+const maxRedirects = 10
+
+// redirectsKey is a request context key holding the request redirect chain.
+type redirectsKey struct{}
+
+// withRedirects returns a copy of ctx, in which the request redirect hops are recorded into the given chain.
+func withRedirects(ctx context.Context, chain *[]redirect) context.Context {
+	return context.WithValue(ctx, redirectsKey{}, chain)
+}
+
+// newHTTPClient creates an HTTP client used for links inspection.
+// External client is used for checking links to external hosts. It uses inspector.externalProxy, if set,
+// or inspector.proxy otherwise. Internal client reads pages from inspector.dir, if set.
+func newHTTPClient(cfg *inspectorConfig, external bool) (*http.Client, error) {
+	auths, err := newHostAuths(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	tr, err := newTransport(cfg, external)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := cfg.RequestTimeout
+	if external {
+		timeout = cfg.ExternalRequestTimeout
+	}
+
+	var rt http.RoundTripper = tr
+	if len(auths) > 0 {
+		rt = &authTransport{base: tr, auths: auths}
+	}
+
+	// internal pages are read from the site directory in offline mode.
+	if !external && cfg.Dir != "" {
+		rt = newDirTransport(cfg.Dir)
+	}
+
+	client := &http.Client{
+		Timeout:       timeout,
+		Transport:     rt,
+		CheckRedirect: newCheckRedirect(cfg),
+	}
+
+	// internal requests share the session.
+	if !external {
+		if client.Jar, err = newCookieJar(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
+
+// newTransport creates an HTTP transport with TLS, proxy and resolve settings from the given configuration.
+func newTransport(cfg *inspectorConfig, external bool) (*http.Transport, error) {
+	tlsCfg, err := newTLSConfig(cfg, external)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := parseResolve(cfg.Resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	proxyCfg := cfg.Proxy
+	if external && cfg.ExternalProxy != nil {
+		proxyCfg = cfg.ExternalProxy
+	}
+
+	proxy, err := newProxyFunc(proxyCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+
+	return &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           newDialContext(dialer, overrides),
+		ForceAttemptHTTP2:     true,
+		MaxIdleConnsPerHost:   1024,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		TLSClientConfig:       tlsCfg,
+		ExpectContinueTimeout: 1 * time.Second,
+	}, nil
+}
+
+// newDialContext creates a dial function connecting to the addresses overriding the dialed hosts, if any.
+func newDialContext(
+	dialer *net.Dialer,
+	overrides map[string]string,
+) func(ctx context.Context, network, address string) (net.Conn, error) {
+	if len(overrides) == 0 {
+		return dialer.DialContext
+	}
+
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		if override, ok := overrides[strings.ToLower(address)]; ok {
+			address = override
+		}
+
+		return dialer.DialContext(ctx, network, address)
+	}
+}
+
+// parseResolve parses inspector.resolve values in the "host:port:address" format, like curl --resolve ones.
+// Address is an IP address, IPv6 ones may be enclosed in brackets, or a host name.
+// It returns a map of dialed "host:port" values to the "address:port" values to connect to instead.
+func parseResolve(values []string) (map[string]string, error) {
+	overrides := make(map[string]string, len(values))
+
+	for _, v := range values {
+		parts := strings.SplitN(v, ":", 3)
+		if len(parts) != 3 {
+			return nil, errorc.With(ErrInvalidInspectorResolveValue, errorc.Field("value", v))
+		}
+
+		host, port, addr := parts[0], parts[1], strings.Trim(parts[2], "[]")
+
+		if _, err := strconv.ParseUint(port, 10, 16); host == "" || addr == "" || err != nil {
+			return nil, errorc.With(ErrInvalidInspectorResolveValue, errorc.Field("value", v))
+		}
+
+		overrides[strings.ToLower(net.JoinHostPort(host, port))] = net.JoinHostPort(addr, port)
+	}
+
+	return overrides, nil
+}
+
+// newCheckRedirect creates a redirect policy according to the given configuration.
+// Redirect hops are recorded into the chain stored in the request context, if any.
+// With inspector.doNotFollowRedirects, the unfollowed redirect is recorded as the single hop.
+func newCheckRedirect(cfg *inspectorConfig) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if !cfg.DoNotFollowRedirects && len(via) >= maxRedirects {
+			return ErrTooManyRedirects
+		}
+
+		// request URL is the response Location header value resolved against the redirected request URL.
+		chain, ok := req.Context().Value(redirectsKey{}).(*[]redirect)
+		if ok && req.Response != nil {
+			*chain = append(*chain, redirect{
+				Code:       req.Response.StatusCode,
+				Location:   req.URL.String(),
+				unfollowed: cfg.DoNotFollowRedirects,
+			})
+		}
+
+		if cfg.DoNotFollowRedirects {
+			return http.ErrUseLastResponse
+		}
+
+		return nil
+	}
+}
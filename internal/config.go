@@ -4,9 +4,12 @@ import (
 	"errors"
 	"io/fs"
 	"net"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,29 +23,146 @@ const (
 
 	envPrefix = "LINKS"
 
-	configKeyInspectorHost           = "inspector.host"
-	configKeyInspectorRequestTimeout = "inspector.requestTimeout"
-	configKeyInspectorRetryAttempts  = "inspector.retryAttempts"
-	configKeyInspectorRetryDelay     = "inspector.retryDelay"
-	configKeyPrinterOutputFormat     = "printer.outputFormat"
-
-	defaultInspectorHost           = ""
-	defaultInspectorRequestTimeout = 30 * time.Second
-	defaultInspectorRetryAttempts  = 3
-	defaultInspectorRetryDelay     = 2 * time.Millisecond
+	configKeyInspectorHost                   = "inspector.host"
+	configKeyInspectorRequestTimeout         = "inspector.requestTimeout"
+	configKeyInspectorExternalRequestTimeout = "inspector.externalRequestTimeout"
+	configKeyInspectorExternalConcurrency    = "inspector.externalConcurrency"
+	configKeyInspectorRetryAttempts          = "inspector.retryAttempts"
+	configKeyInspectorRetryDelay             = "inspector.retryDelay"
+	configKeyInspectorRetryMaxDelay          = "inspector.retryMaxDelay"
+	configKeyInspectorRetryStatusCodes       = "inspector.retryStatusCodes"
+	configKeyPrinterOutputFormat             = "printer.outputFormat"
+
+	defaultInspectorHost                   = ""
+	defaultInspectorRequestTimeout         = 30 * time.Second
+	defaultInspectorExternalRequestTimeout = 10 * time.Second
+	defaultInspectorExternalConcurrency    = 4
+	defaultInspectorRetryAttempts          = 3
+	defaultInspectorRetryDelay             = 2 * time.Millisecond
+	defaultInspectorRetryMaxDelay          = 30 * time.Second
 )
 
+// defaultInspectorRetryStatusCodes are response status codes, which are retried by default.
+var defaultInspectorRetryStatusCodes = []int{http.StatusTooManyRequests, http.StatusServiceUnavailable}
+
 // inspectorConfig is a configuration for the inspector.
 //
 //nolint:lll // ignore long lines.
 type inspectorConfig struct {
-	Host                 string        `mapstructure:"host" yaml:"host,omitempty" json:"host,omitempty"`
-	RequestTimeout       time.Duration `mapstructure:"requestTimeout" yaml:"requestTimeout,omitempty" json:"requestTimeout,omitempty"`
-	DoNotFollowRedirects bool          `mapstructure:"doNotFollowRedirects" yaml:"doNotFollowRedirects" json:"doNotFollowRedirects"`
-	LogExternalLinks     bool          `mapstructure:"logExternalLinks" yaml:"logExternalLinks" json:"logExternalLinks"`
-	SkipStatusCodes      []int         `mapstructure:"skipStatusCodes" yaml:"skipStatusCodes,omitempty" json:"skipStatusCodes,omitempty"`
-	RetryAttempts        byte          `mapstructure:"retryAttempts" yaml:"retryAttempts" json:"retryAttempts"`
-	RetryDelay           time.Duration `mapstructure:"retryDelay" yaml:"retryDelay,omitempty" json:"retryDelay,omitempty"`
+	Host                   string         `mapstructure:"host" yaml:"host,omitempty" json:"host,omitempty"`
+	RequestTimeout         time.Duration  `mapstructure:"requestTimeout" yaml:"requestTimeout,omitempty" json:"requestTimeout,omitempty"`
+	DoNotFollowRedirects   bool           `mapstructure:"doNotFollowRedirects" yaml:"doNotFollowRedirects" json:"doNotFollowRedirects"`
+	LogExternalLinks       bool           `mapstructure:"logExternalLinks" yaml:"logExternalLinks" json:"logExternalLinks"`
+	CheckExternalLinks     bool           `mapstructure:"checkExternalLinks" yaml:"checkExternalLinks" json:"checkExternalLinks"`
+	ExternalRequestTimeout time.Duration  `mapstructure:"externalRequestTimeout" yaml:"externalRequestTimeout,omitempty" json:"externalRequestTimeout,omitempty"`
+	ExternalConcurrency    uint           `mapstructure:"externalConcurrency" yaml:"externalConcurrency,omitempty" json:"externalConcurrency,omitempty"`
+	Extractors             []string       `mapstructure:"extractors" yaml:"extractors,omitempty" json:"extractors,omitempty"`
+	SkipStatusCodes        []int          `mapstructure:"skipStatusCodes" yaml:"skipStatusCodes,omitempty" json:"skipStatusCodes,omitempty"`
+	RetryAttempts          byte           `mapstructure:"retryAttempts" yaml:"retryAttempts" json:"retryAttempts"`
+	RetryDelay             time.Duration  `mapstructure:"retryDelay" yaml:"retryDelay,omitempty" json:"retryDelay,omitempty"`
+	RetryMaxDelay          time.Duration  `mapstructure:"retryMaxDelay" yaml:"retryMaxDelay,omitempty" json:"retryMaxDelay,omitempty"`
+	RetryStatusCodes       []int          `mapstructure:"retryStatusCodes" yaml:"retryStatusCodes,omitempty" json:"retryStatusCodes,omitempty"`
+	FailOn                 []string       `mapstructure:"failOn" yaml:"failOn,omitempty" json:"failOn,omitempty"`
+	FailThreshold          int            `mapstructure:"failThreshold" yaml:"failThreshold,omitempty" json:"failThreshold,omitempty"`
+	UserAgent              string         `mapstructure:"userAgent" yaml:"userAgent,omitempty" json:"userAgent,omitempty"`
+	IgnoreRobotsTxt        bool           `mapstructure:"ignoreRobotsTxt" yaml:"ignoreRobotsTxt" json:"ignoreRobotsTxt"`
+	Sitemap                string         `mapstructure:"sitemap" yaml:"sitemap,omitempty" json:"sitemap,omitempty"`
+	MaxDepth               uint           `mapstructure:"maxDepth" yaml:"maxDepth,omitempty" json:"maxDepth,omitempty"`
+	MaxPages               uint           `mapstructure:"maxPages" yaml:"maxPages,omitempty" json:"maxPages,omitempty"`
+	Include                []string       `mapstructure:"include" yaml:"include,omitempty" json:"include,omitempty"`
+	Exclude                []string       `mapstructure:"exclude" yaml:"exclude,omitempty" json:"exclude,omitempty"`
+	ReportExcluded         bool           `mapstructure:"reportExcluded" yaml:"reportExcluded" json:"reportExcluded"`
+	Concurrency            uint           `mapstructure:"concurrency" yaml:"concurrency,omitempty" json:"concurrency,omitempty"`
+	ParserConcurrency      uint           `mapstructure:"parserConcurrency" yaml:"parserConcurrency,omitempty" json:"parserConcurrency,omitempty"`
+	RequestsPerSecond      float64        `mapstructure:"requestsPerSecond" yaml:"requestsPerSecond,omitempty" json:"requestsPerSecond,omitempty"`
+	Auth                   []authConfig   `mapstructure:"auth" yaml:"auth,omitempty" json:"auth,omitempty"`
+	Cookies                []cookieConfig `mapstructure:"cookies" yaml:"cookies,omitempty" json:"cookies,omitempty"`
+	CookiesFile            string         `mapstructure:"cookiesFile" yaml:"cookiesFile,omitempty" json:"cookiesFile,omitempty"`
+	Login                  *loginConfig   `mapstructure:"login" yaml:"login,omitempty" json:"login,omitempty"`
+	TLS                    *tlsConfig     `mapstructure:"tls" yaml:"tls,omitempty" json:"tls,omitempty"`
+	Resolve                []string       `mapstructure:"resolve" yaml:"resolve,omitempty" json:"resolve,omitempty"`
+	Proxy                  *proxyConfig   `mapstructure:"proxy" yaml:"proxy,omitempty" json:"proxy,omitempty"`
+	ExternalProxy          *proxyConfig   `mapstructure:"externalProxy" yaml:"externalProxy,omitempty" json:"externalProxy,omitempty"`
+	InternalHosts          []string       `mapstructure:"internalHosts" yaml:"internalHosts,omitempty" json:"internalHosts,omitempty"`
+	StartURLs              []string       `mapstructure:"startURLs" yaml:"startURLs,omitempty" json:"startURLs,omitempty"`
+	StartURLsFile          string         `mapstructure:"startURLsFile" yaml:"startURLsFile,omitempty" json:"startURLsFile,omitempty"`
+	Dir                    string         `mapstructure:"dir" yaml:"dir,omitempty" json:"dir,omitempty"`
+}
+
+// authConfig is a configuration of credentials and headers sent in requests to a host.
+// Empty host stands for the inspected host.
+// Values prefixed with "env:" and "file:" are read from the environment variable and the file respectively.
+//
+//nolint:lll // ignore long lines.
+type authConfig struct {
+	Host        string            `mapstructure:"host" yaml:"host,omitempty" json:"host,omitempty"`
+	Username    string            `mapstructure:"username" yaml:"username,omitempty" json:"username,omitempty"`
+	Password    string            `mapstructure:"password" yaml:"password,omitempty" json:"password,omitempty"`
+	BearerToken string            `mapstructure:"bearerToken" yaml:"bearerToken,omitempty" json:"bearerToken,omitempty"`
+	Headers     map[string]string `mapstructure:"headers" yaml:"headers,omitempty" json:"headers,omitempty"`
+}
+
+// cookieConfig is a cookie sent in requests to the inspected host.
+// Empty domain stands for the inspected host, empty path stands for the root path.
+// Value may be read from the environment variable or the file, like inspector.auth values.
+//
+//nolint:lll // ignore long lines.
+type cookieConfig struct {
+	Name   string `mapstructure:"name" yaml:"name" json:"name"`
+	Value  string `mapstructure:"value" yaml:"value" json:"value"`
+	Domain string `mapstructure:"domain" yaml:"domain,omitempty" json:"domain,omitempty"`
+	Path   string `mapstructure:"path" yaml:"path,omitempty" json:"path,omitempty"`
+}
+
+// loginConfig is a configuration of the login form submitted before inspection.
+// Login succeeds, if the response status code is below 400, the response body contains successText
+// and the successCookie cookie is set, if any of them is not empty.
+//
+//nolint:lll // ignore long lines.
+type loginConfig struct {
+	URL           string      `mapstructure:"url" yaml:"url" json:"url"`
+	Fields        []formField `mapstructure:"fields" yaml:"fields,omitempty" json:"fields,omitempty"`
+	SuccessText   string      `mapstructure:"successText" yaml:"successText,omitempty" json:"successText,omitempty"`
+	SuccessCookie string      `mapstructure:"successCookie" yaml:"successCookie,omitempty" json:"successCookie,omitempty"`
+}
+
+// tlsConfig is a configuration of TLS connections.
+// Root CA certificates are added to the system ones. Client certificates are only used for internal requests.
+//
+//nolint:lll // ignore long lines.
+type tlsConfig struct {
+	CAFile             string              `mapstructure:"caFile" yaml:"caFile,omitempty" json:"caFile,omitempty"`
+	Certificates       []certificateConfig `mapstructure:"certificates" yaml:"certificates,omitempty" json:"certificates,omitempty"`
+	MinVersion         string              `mapstructure:"minVersion" yaml:"minVersion,omitempty" json:"minVersion,omitempty"`
+	InsecureSkipVerify bool                `mapstructure:"insecureSkipVerify" yaml:"insecureSkipVerify" json:"insecureSkipVerify"`
+}
+
+// certificateConfig is a client certificate and its private key PEM files paths.
+//
+//nolint:lll // ignore long lines.
+type certificateConfig struct {
+	CertFile string `mapstructure:"certFile" yaml:"certFile" json:"certFile"`
+	KeyFile  string `mapstructure:"keyFile" yaml:"keyFile" json:"keyFile"`
+}
+
+// proxyConfig is a configuration of the proxy used for requests.
+// URL is an http, https, socks5 or socks5h proxy URL, or "direct" for connecting without proxy.
+// Username and password may be read from environment variables or files, like inspector.auth values.
+// NoProxy lists hosts, domains, and IP ranges connected without proxy, in the NO_PROXY environment variable format.
+//
+//nolint:lll // ignore long lines.
+type proxyConfig struct {
+	URL      string   `mapstructure:"url" yaml:"url" json:"url"`
+	Username string   `mapstructure:"username" yaml:"username,omitempty" json:"username,omitempty"`
+	Password string   `mapstructure:"password" yaml:"password,omitempty" json:"password,omitempty"`
+	NoProxy  []string `mapstructure:"noProxy" yaml:"noProxy,omitempty" json:"noProxy,omitempty"`
+}
+
+// formField is a form field submitted on login.
+// Value may be read from the environment variable or the file, like inspector.auth values.
+type formField struct {
+	Name  string `mapstructure:"name" yaml:"name" json:"name"`
+	Value string `mapstructure:"value" yaml:"value" json:"value"`
 }
 
 // printerConfig is a configuration for the printer.
@@ -51,8 +171,10 @@ type inspectorConfig struct {
 type printerConfig struct {
 	SortOutput          bool         `mapstructure:"sortOutput" yaml:"sortOutput" json:"sortOutput"`
 	DisplayOccurrences  bool         `mapstructure:"displayOccurrences" yaml:"displayOccurrences" json:"displayOccurrences"`
+	DisplayReferrers    bool         `mapstructure:"displayReferrers" yaml:"displayReferrers" json:"displayReferrers"`
 	SkipOK              bool         `mapstructure:"skipOK" yaml:"skipOK" json:"skipOK"`
 	OutputFormat        outputFormat `mapstructure:"outputFormat" yaml:"-" json:"-"`
+	OutputPath          string       `mapstructure:"outputPath" yaml:"outputPath,omitempty" json:"outputPath,omitempty"`
 	DoNotOpenFileReport bool         `mapstructure:"doNotOpenFileReport" yaml:"doNotOpenFileReport" json:"doNotOpenFileReport"`
 }
 
@@ -62,8 +184,30 @@ type config struct {
 }
 
 func (c *config) validate() error {
+	// start URLs and site directory may define the inspected host.
+	if err := c.validateInspectorStartURLs(); err != nil {
+		return err
+	}
+
+	if err := c.validateInspectorDir(); err != nil {
+		return err
+	}
+
 	return errors.Join(
 		c.validateInspectorHost(),
+		c.validateInspectorExtractors(),
+		c.validateInspectorFailOn(),
+		c.validateInspectorScope(),
+		c.validateInspectorRequestsPerSecond(),
+		c.validateInspectorRetryDelays(),
+		c.validateInspectorAuth(),
+		c.validateInspectorCookies(),
+		c.validateInspectorLogin(),
+		c.validateInspectorTLS(),
+		c.validateInspectorResolve(),
+		c.validateInspectorInternalHosts(),
+		validateProxy(c.Inspector.Proxy, ErrInvalidInspectorProxyValue),
+		validateProxy(c.Inspector.ExternalProxy, ErrInvalidInspectorExternalProxyValue),
 		c.validatePrinterOutputFormat(),
 	)
 }
@@ -97,10 +241,220 @@ func (c *config) validateInspectorHost() error {
 	return nil
 }
 
+// validateInspectorStartURLs appends URLs read from inspector.startURLsFile to inspector.startURLs.
+// In case inspector.host is not set, it is taken from the first absolute start URL.
+func (c *config) validateInspectorStartURLs() error {
+	if c.Inspector.StartURLsFile != "" {
+		urls, err := readStartURLsFile(c.Inspector.StartURLsFile)
+		if err != nil {
+			return errorc.With(
+				ErrInvalidInspectorStartURLsFileValue,
+				errorc.Field("path", c.Inspector.StartURLsFile),
+				errorc.Field("reason", err.Error()),
+			)
+		}
+
+		c.Inspector.StartURLs = append(c.Inspector.StartURLs, urls...)
+	}
+
+	for _, v := range c.Inspector.StartURLs {
+		u, err := url.Parse(v)
+		if err != nil || (u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https") {
+			return errorc.With(ErrInvalidInspectorStartURLsValue, errorc.Field("value", v))
+		}
+
+		if c.Inspector.Host == "" && u.IsAbs() {
+			c.Inspector.Host = u.Scheme + "://" + u.Host
+		}
+	}
+
+	return nil
+}
+
+// validateInspectorDir checks that inspector.dir is a directory.
+// In case inspector.host is not set, the site is inspected as served on localhost.
+func (c *config) validateInspectorDir() error {
+	if c.Inspector.Dir == "" {
+		return nil
+	}
+
+	reason := ""
+
+	info, err := os.Stat(c.Inspector.Dir)
+	switch {
+	case err != nil:
+		reason = err.Error()
+
+	case !info.IsDir():
+		reason = "not a directory"
+	}
+
+	if reason != "" {
+		return errorc.With(
+			ErrInvalidInspectorDirValue,
+			errorc.Field("path", c.Inspector.Dir),
+			errorc.Field("reason", reason),
+		)
+	}
+
+	if c.Inspector.Host == "" {
+		c.Inspector.Host = defaultDirHost
+	}
+
+	return nil
+}
+
+func (c *config) validateInspectorExtractors() error {
+	if _, err := newExtractors(c.Inspector.Extractors); err != nil {
+		return errorc.With(err, errorc.Field("value", strings.Join(c.Inspector.Extractors, ",")))
+	}
+
+	return nil
+}
+
+func (c *config) validateInspectorFailOn() error {
+	for _, v := range c.Inspector.FailOn {
+		if !isValidFailureCriterion(v) {
+			return errorc.With(ErrInvalidInspectorFailOnValue, errorc.Field("value", v))
+		}
+	}
+
+	return nil
+}
+
+func (c *config) validateInspectorScope() error {
+	_, err := newScope(c.Inspector.Include, c.Inspector.Exclude)
+
+	return err
+}
+
+func (c *config) validateInspectorRequestsPerSecond() error {
+	if c.Inspector.RequestsPerSecond < 0 {
+		return errorc.With(
+			ErrInvalidInspectorRequestsPerSecondValue,
+			errorc.Field("value", strconv.FormatFloat(c.Inspector.RequestsPerSecond, 'f', -1, 64)),
+		)
+	}
+
+	return nil
+}
+
+// validateInspectorRetryDelays rejects negative retry delays, exponential backoff requires non-negative ones.
+func (c *config) validateInspectorRetryDelays() error {
+	var errs []error
+
+	if c.Inspector.RetryDelay < 0 {
+		errs = append(errs, errorc.With(
+			ErrInvalidInspectorRetryDelayValue,
+			errorc.Field("value", c.Inspector.RetryDelay.String()),
+		))
+	}
+
+	if c.Inspector.RetryMaxDelay < 0 {
+		errs = append(errs, errorc.With(
+			ErrInvalidInspectorRetryMaxDelayValue,
+			errorc.Field("value", c.Inspector.RetryMaxDelay.String()),
+		))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *config) validateInspectorAuth() error {
+	for _, a := range c.Inspector.Auth {
+		u, err := url.Parse("//" + a.Host)
+		switch {
+		case err != nil || u.Host != a.Host:
+			return errorc.With(ErrInvalidInspectorAuthValue, errorc.Field("host", a.Host))
+
+		case (a.Username != "" || a.Password != "") && a.BearerToken != "":
+			return errorc.With(
+				ErrInvalidInspectorAuthValue,
+				errorc.Field("host", a.Host),
+				errorc.Field("reason", "both basic auth credentials and bearer token are set"),
+			)
+		}
+	}
+
+	return nil
+}
+
+func (c *config) validateInspectorCookies() error {
+	for _, cookie := range c.Inspector.Cookies {
+		if cookie.Name == "" {
+			return errorc.With(ErrInvalidInspectorCookiesValue, errorc.Field("reason", "empty cookie name"))
+		}
+	}
+
+	return nil
+}
+
+func (c *config) validateInspectorLogin() error {
+	if c.Inspector.Login == nil {
+		return nil
+	}
+
+	if c.Inspector.Login.URL == "" {
+		return errorc.With(ErrInvalidInspectorLoginValue, errorc.Field("reason", "empty login url"))
+	}
+
+	if _, err := url.Parse(c.Inspector.Login.URL); err != nil {
+		return errorc.With(ErrInvalidInspectorLoginValue, errorc.Field("url", c.Inspector.Login.URL))
+	}
+
+	return nil
+}
+
+func (c *config) validateInspectorTLS() error {
+	if c.Inspector.TLS == nil {
+		return nil
+	}
+
+	if _, ok := tlsVersions[c.Inspector.TLS.MinVersion]; !ok {
+		return errorc.With(ErrInvalidInspectorTLSValue, errorc.Field("minVersion", c.Inspector.TLS.MinVersion))
+	}
+
+	for _, cert := range c.Inspector.TLS.Certificates {
+		if cert.CertFile == "" || cert.KeyFile == "" {
+			return errorc.With(ErrInvalidInspectorTLSValue, errorc.Field("reason", "empty certificate or key file"))
+		}
+	}
+
+	return nil
+}
+
+func (c *config) validateInspectorResolve() error {
+	_, err := parseResolve(c.Inspector.Resolve)
+
+	return err
+}
+
+func (c *config) validateInspectorInternalHosts() error {
+	_, err := newHostPatterns(c.Inspector.InternalHosts)
+
+	return err
+}
+
+func validateProxy(p *proxyConfig, errInvalid error) error {
+	if p == nil || p.URL == proxyDirect {
+		return nil
+	}
+
+	u, err := url.Parse(p.URL)
+	if err != nil || u.Host == "" || !slices.Contains(proxySchemes, u.Scheme) {
+		return errorc.With(errInvalid, errorc.Field("url", p.URL))
+	}
+
+	return nil
+}
+
 func (c *config) validatePrinterOutputFormat() error {
 	if c.Printer.OutputFormat != outputFormatStdOut &&
 		c.Printer.OutputFormat != outputFormatHTML &&
-		c.Printer.OutputFormat != outputFormatCSV {
+		c.Printer.OutputFormat != outputFormatCSV &&
+		c.Printer.OutputFormat != outputFormatJUnit &&
+		c.Printer.OutputFormat != outputFormatJSON &&
+		c.Printer.OutputFormat != outputFormatNDJSON {
 		return errorc.With(
 			ErrInvalidPrinterOutputFormatValue,
 			errorc.Field("value", string(c.Printer.OutputFormat)),
@@ -158,6 +512,23 @@ func newConfig(cfgFile string, deps injectables) (*config, error) {
 	return cfg, err
 }
 
+// isEmptyHostError checks whether the configuration error is a single ErrEmptyHostValue error.
+func isEmptyHostError(err error) bool {
+	for {
+		joined, ok := err.(interface{ Unwrap() []error })
+		if !ok {
+			return errors.Is(err, ErrEmptyHostValue)
+		}
+
+		errs := joined.Unwrap()
+		if len(errs) != 1 {
+			return false
+		}
+
+		err = errs[0]
+	}
+}
+
 func getConfigFilePath(createDir bool, deps injectables) (string, error) {
 	userCfgDir, err := deps.getUserConfigDir()()
 	if err != nil {
@@ -178,7 +549,11 @@ func getConfigFilePath(createDir bool, deps injectables) (string, error) {
 func setDefaults() {
 	viper.SetDefault(configKeyInspectorHost, defaultInspectorHost) // env variable value is not read without this.
 	viper.SetDefault(configKeyInspectorRequestTimeout, defaultInspectorRequestTimeout)
+	viper.SetDefault(configKeyInspectorExternalRequestTimeout, defaultInspectorExternalRequestTimeout)
+	viper.SetDefault(configKeyInspectorExternalConcurrency, defaultInspectorExternalConcurrency)
 	viper.SetDefault(configKeyInspectorRetryAttempts, defaultInspectorRetryAttempts)
 	viper.SetDefault(configKeyInspectorRetryDelay, defaultInspectorRetryDelay)
+	viper.SetDefault(configKeyInspectorRetryMaxDelay, defaultInspectorRetryMaxDelay)
+	viper.SetDefault(configKeyInspectorRetryStatusCodes, defaultInspectorRetryStatusCodes)
 	viper.SetDefault(configKeyPrinterOutputFormat, outputFormatStdOut)
 }
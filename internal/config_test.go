@@ -18,6 +18,7 @@ func TestNewConfig(t *testing.T) {
 		cfgFile     string
 		before      func(t *testing.T) injectables
 		expected    *config
+		expectedFn  func(t *testing.T, c *config)
 		expectedErr string
 	}{
 		{
@@ -33,10 +34,14 @@ func TestNewConfig(t *testing.T) {
 			},
 			expected: &config{
 				Inspector: &inspectorConfig{
-					Host:           "http://localhost",
-					RequestTimeout: 30 * time.Second,
-					RetryDelay:     2 * time.Millisecond,
-					RetryAttempts:  3,
+					Host:                   "http://localhost",
+					RequestTimeout:         30 * time.Second,
+					ExternalRequestTimeout: 10 * time.Second,
+					ExternalConcurrency:    4,
+					RetryDelay:             2 * time.Millisecond,
+					RetryMaxDelay:          30 * time.Second,
+					RetryStatusCodes:       []int{429, 503},
+					RetryAttempts:          3,
 				},
 				Printer: printerConfig{
 					OutputFormat: outputFormatStdOut,
@@ -80,10 +85,14 @@ func TestNewConfig(t *testing.T) {
 			},
 			expected: &config{
 				Inspector: &inspectorConfig{
-					RequestTimeout: 30 * time.Second,
-					RetryDelay:     2 * time.Millisecond,
-					RetryAttempts:  10,
-					Host:           "http://testhost",
+					RequestTimeout:         30 * time.Second,
+					ExternalRequestTimeout: 10 * time.Second,
+					ExternalConcurrency:    4,
+					RetryDelay:             2 * time.Millisecond,
+					RetryMaxDelay:          30 * time.Second,
+					RetryStatusCodes:       []int{429, 503},
+					RetryAttempts:          10,
+					Host:                   "http://testhost",
 				},
 				Printer: printerConfig{
 					SortOutput:   true,
@@ -106,6 +115,272 @@ func TestNewConfig(t *testing.T) {
 			expectedErr: ErrInvalidPrinterOutputFormatValue.Error(),
 		},
 
+		{
+			name: "invalid extractors",
+			before: func(t *testing.T) injectables {
+				dir := t.TempDir()
+
+				testCfgDir := filepath.Join(dir, defaultCfgDir)
+
+				err := os.Mkdir(testCfgDir, 0o700)
+				require.NoError(t, err)
+
+				testCfgFile := filepath.Join(testCfgDir, defaultCfgFile)
+
+				b := []byte(`inspector:
+    host: localhost
+    extractors:
+        - a@href
+        - img`)
+
+				err = os.WriteFile(testCfgFile, b, 0o600)
+				require.NoError(t, err)
+
+				return injectables{
+					userConfigDir: func() (string, error) {
+						return dir, nil
+					},
+				}
+			},
+			expectedErr: ErrInvalidInspectorExtractorValue.Error(),
+		},
+
+		{
+			name: "invalid exclude",
+			before: func(t *testing.T) injectables {
+				dir := t.TempDir()
+
+				testCfgDir := filepath.Join(dir, defaultCfgDir)
+
+				err := os.Mkdir(testCfgDir, 0o700)
+				require.NoError(t, err)
+
+				testCfgFile := filepath.Join(testCfgDir, defaultCfgFile)
+
+				b := []byte(`inspector:
+    host: localhost
+    exclude:
+        - /api/**
+        - re:/calendar/(`)
+
+				err = os.WriteFile(testCfgFile, b, 0o600)
+				require.NoError(t, err)
+
+				return injectables{
+					userConfigDir: func() (string, error) {
+						return dir, nil
+					},
+				}
+			},
+			expectedErr: ErrInvalidInspectorExcludeValue.Error(),
+		},
+
+		{
+			name: "invalid auth",
+			before: func(t *testing.T) injectables {
+				dir := t.TempDir()
+
+				testCfgDir := filepath.Join(dir, defaultCfgDir)
+
+				err := os.Mkdir(testCfgDir, 0o700)
+				require.NoError(t, err)
+
+				testCfgFile := filepath.Join(testCfgDir, defaultCfgFile)
+
+				b := []byte(`inspector:
+    host: localhost
+    auth:
+        - username: user
+          password: env:PASSWORD
+          bearerToken: file:/run/secrets/token`)
+
+				err = os.WriteFile(testCfgFile, b, 0o600)
+				require.NoError(t, err)
+
+				return injectables{
+					userConfigDir: func() (string, error) {
+						return dir, nil
+					},
+				}
+			},
+			expectedErr: ErrInvalidInspectorAuthValue.Error(),
+		},
+
+		{
+			name: "negative retry delay",
+			before: func(t *testing.T) injectables {
+				dir := t.TempDir()
+
+				testCfgDir := filepath.Join(dir, defaultCfgDir)
+
+				err := os.Mkdir(testCfgDir, 0o700)
+				require.NoError(t, err)
+
+				testCfgFile := filepath.Join(testCfgDir, defaultCfgFile)
+
+				b := []byte(`inspector:
+    host: localhost
+    retryDelay: -5ms`)
+
+				err = os.WriteFile(testCfgFile, b, 0o600)
+				require.NoError(t, err)
+
+				return injectables{
+					userConfigDir: func() (string, error) {
+						return dir, nil
+					},
+				}
+			},
+			expectedErr: ErrInvalidInspectorRetryDelayValue.Error(),
+		},
+
+		{
+			name: "invalid tls min version",
+			before: func(t *testing.T) injectables {
+				dir := t.TempDir()
+
+				testCfgDir := filepath.Join(dir, defaultCfgDir)
+
+				err := os.Mkdir(testCfgDir, 0o700)
+				require.NoError(t, err)
+
+				testCfgFile := filepath.Join(testCfgDir, defaultCfgFile)
+
+				b := []byte(`inspector:
+    host: localhost
+    tls:
+        minVersion: "1.4"`)
+
+				err = os.WriteFile(testCfgFile, b, 0o600)
+				require.NoError(t, err)
+
+				return injectables{
+					userConfigDir: func() (string, error) {
+						return dir, nil
+					},
+				}
+			},
+			expectedErr: ErrInvalidInspectorTLSValue.Error(),
+		},
+
+		{
+			name: "invalid external proxy",
+			before: func(t *testing.T) injectables {
+				dir := t.TempDir()
+
+				testCfgDir := filepath.Join(dir, defaultCfgDir)
+
+				err := os.Mkdir(testCfgDir, 0o700)
+				require.NoError(t, err)
+
+				testCfgFile := filepath.Join(testCfgDir, defaultCfgFile)
+
+				b := []byte(`inspector:
+    host: localhost
+    proxy:
+        url: direct
+    externalProxy:
+        url: ftp://proxy.example.com`)
+
+				err = os.WriteFile(testCfgFile, b, 0o600)
+				require.NoError(t, err)
+
+				return injectables{
+					userConfigDir: func() (string, error) {
+						return dir, nil
+					},
+				}
+			},
+			expectedErr: ErrInvalidInspectorExternalProxyValue.Error(),
+		},
+
+		{
+			name: "start URLs file",
+			before: func(t *testing.T) injectables {
+				dir := t.TempDir()
+
+				testCfgDir := filepath.Join(dir, defaultCfgDir)
+
+				err := os.Mkdir(testCfgDir, 0o700)
+				require.NoError(t, err)
+
+				urlsFile := filepath.Join(dir, "urls.txt")
+				err = os.WriteFile(urlsFile, []byte("# sites\nhttps://docs.example.com/guide\n\n/blog\n"), 0o600)
+				require.NoError(t, err)
+
+				b := []byte(`inspector:
+    startURLs:
+        - /start
+    startURLsFile: ` + urlsFile)
+
+				err = os.WriteFile(filepath.Join(testCfgDir, defaultCfgFile), b, 0o600)
+				require.NoError(t, err)
+
+				return injectables{
+					userConfigDir: func() (string, error) {
+						return dir, nil
+					},
+				}
+			},
+			expectedFn: func(t *testing.T, c *config) {
+				require.Equal(t, "https://docs.example.com", c.Inspector.Host)
+				require.Equal(t, []string{"/start", "https://docs.example.com/guide", "/blog"}, c.Inspector.StartURLs)
+			},
+		},
+
+		{
+			name: "invalid start URL",
+			before: func(t *testing.T) injectables {
+				t.Setenv("LINKS_INSPECTOR_HOST", "localhost")
+
+				dir := t.TempDir()
+
+				testCfgDir := filepath.Join(dir, defaultCfgDir)
+
+				err := os.Mkdir(testCfgDir, 0o700)
+				require.NoError(t, err)
+
+				b := []byte(`inspector:
+    startURLs:
+        - ftp://files.example.com`)
+
+				err = os.WriteFile(filepath.Join(testCfgDir, defaultCfgFile), b, 0o600)
+				require.NoError(t, err)
+
+				return injectables{
+					userConfigDir: func() (string, error) {
+						return dir, nil
+					},
+				}
+			},
+			expectedErr: ErrInvalidInspectorStartURLsValue.Error(),
+		},
+
+		{
+			name: "invalid dir",
+			before: func(t *testing.T) injectables {
+				dir := t.TempDir()
+
+				testCfgDir := filepath.Join(dir, defaultCfgDir)
+
+				err := os.Mkdir(testCfgDir, 0o700)
+				require.NoError(t, err)
+
+				b := []byte(`inspector:
+    dir: ` + filepath.Join(dir, "missing"))
+
+				err = os.WriteFile(filepath.Join(testCfgDir, defaultCfgFile), b, 0o600)
+				require.NoError(t, err)
+
+				return injectables{
+					userConfigDir: func() (string, error) {
+						return dir, nil
+					},
+				}
+			},
+			expectedErr: ErrInvalidInspectorDirValue.Error(),
+		},
+
 		{
 			name: "os.stat error",
 			before: func(t *testing.T) injectables {
@@ -185,9 +460,15 @@ func TestNewConfig(t *testing.T) {
 
 			c, cErr := newConfig(test.cfgFile, deps)
 
-			if test.expectedErr != "" {
+			switch {
+			case test.expectedErr != "":
 				require.ErrorContains(t, cErr, test.expectedErr)
-			} else {
+
+			case test.expectedFn != nil:
+				require.Nil(t, cErr)
+				test.expectedFn(t, c)
+
+			default:
 				require.Nil(t, cErr)
 				require.Equal(t, test.expected, c)
 			}
@@ -195,3 +476,26 @@ func TestNewConfig(t *testing.T) {
 	}
 	viper.Reset()
 }
+
+func TestIsEmptyHostError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "empty host", err: ErrEmptyHostValue, expected: true},
+		{name: "joined empty host", err: errors.Join(nil, ErrEmptyHostValue, nil), expected: true},
+		{name: "other error", err: ErrInvalidHostValue, expected: false},
+		{
+			name:     "empty host joined with other errors",
+			err:      errors.Join(ErrEmptyHostValue, ErrInvalidPrinterOutputFormatValue, ErrInvalidInspectorFailOnValue),
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, isEmptyHostError(test.err))
+		})
+	}
+}
@@ -15,11 +15,17 @@ var outYAML = `inspector:
     requestTimeout: 30s
     doNotFollowRedirects: false
     logExternalLinks: false
+    checkExternalLinks: false
+    externalRequestTimeout: 10s
+    externalConcurrency: 4
     retryAttempts: 0
     retryDelay: 2ms
+    ignoreRobotsTxt: false
+    reportExcluded: false
 printer:
     sortOutput: true
     displayOccurrences: false
+    displayReferrers: false
     skipOK: false
     doNotOpenFileReport: false
 `
@@ -83,9 +89,11 @@ func TestConfigurator_Show(t *testing.T) {
 			name: "yaml",
 			cfg: &config{
 				Inspector: &inspectorConfig{
-					Host:           "http://localhost",
-					RequestTimeout: 30 * time.Second,
-					RetryDelay:     2 * time.Millisecond,
+					Host:                   "http://localhost",
+					RequestTimeout:         30 * time.Second,
+					ExternalRequestTimeout: 10 * time.Second,
+					ExternalConcurrency:    4,
+					RetryDelay:             2 * time.Millisecond,
 				},
 				Printer: printerConfig{
 					SortOutput: true,
@@ -110,12 +118,16 @@ func TestConfigurator_Show(t *testing.T) {
 	"inspector": {
 		"doNotFollowRedirects": false,
 		"logExternalLinks": false,
+		"checkExternalLinks": false,
 		"retryAttempts": 0,
-		"retryDelay": 2000000
+		"retryDelay": 2000000,
+		"ignoreRobotsTxt": false,
+		"reportExcluded": false
 	},
 	"printer": {
 		"sortOutput": true,
 		"displayOccurrences": false,
+		"displayReferrers": false,
 		"skipOK": false,
 		"doNotOpenFileReport": false
 	}
@@ -126,9 +138,11 @@ func TestConfigurator_Show(t *testing.T) {
 			name: "default",
 			cfg: &config{
 				Inspector: &inspectorConfig{
-					Host:           "http://localhost",
-					RequestTimeout: 30 * time.Second,
-					RetryDelay:     2 * time.Millisecond,
+					Host:                   "http://localhost",
+					RequestTimeout:         30 * time.Second,
+					ExternalRequestTimeout: 10 * time.Second,
+					ExternalConcurrency:    4,
+					RetryDelay:             2 * time.Millisecond,
 				},
 				Printer: printerConfig{
 					SortOutput: true,
@@ -189,11 +203,21 @@ func TestConfigurator_Set(t *testing.T) {
     requestTimeout: 30s
     doNotFollowRedirects: false
     logExternalLinks: false
+    checkExternalLinks: false
+    externalRequestTimeout: 10s
+    externalConcurrency: 4
     retryAttempts: 3
     retryDelay: 2ms
+    retryMaxDelay: 30s
+    retryStatusCodes:
+        - 429
+        - 503
+    ignoreRobotsTxt: false
+    reportExcluded: false
 printer:
     sortOutput: false
     displayOccurrences: false
+    displayReferrers: false
     skipOK: false
     doNotOpenFileReport: false
 `
@@ -211,11 +235,21 @@ printer:
     requestTimeout: 30s
     doNotFollowRedirects: false
     logExternalLinks: false
+    checkExternalLinks: false
+    externalRequestTimeout: 10s
+    externalConcurrency: 4
     retryAttempts: 0
     retryDelay: 2ms
+    retryMaxDelay: 30s
+    retryStatusCodes:
+        - 429
+        - 503
+    ignoreRobotsTxt: false
+    reportExcluded: false
 printer:
     sortOutput: false
     displayOccurrences: false
+    displayReferrers: false
     skipOK: false
     doNotOpenFileReport: false
 `
@@ -234,7 +268,7 @@ printer:
 	require.NoError(t, err)
 	err = c.show(outputFormatYAML)
 	require.NoError(t, err)
-	require.Equal(t, outYAML, actual)
+	require.Equal(t, strings.Replace(expected, "sortOutput: false", "sortOutput: true", 1), actual)
 
 	viper.Reset()
 }
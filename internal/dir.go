@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"path"
+)
+
+const (
+	// defaultDirHost is the inspected host used for a site directory, if inspector.host is not set.
+	defaultDirHost = "http://localhost"
+
+	// indexFile is a file served for a directory path.
+	indexFile = "index.html"
+
+	// cleanURLExtension is an extension of files served for clean URLs, like "/page" for "page.html".
+	cleanURLExtension = ".html"
+)
+
+// siteDir is a static site build directory served like by static site hosts.
+// Directory paths are served from their index files, directories without index files are not found.
+// Clean URLs are served from files with ".html" extension.
+type siteDir struct {
+	fs http.FileSystem
+}
+
+// newDirTransport creates a transport serving requests from inspector.dir instead of doing HTTP requests.
+// Requests hosts are ignored. Directory paths without trailing slash are redirected to the ones with it.
+func newDirTransport(dir string) http.RoundTripper {
+	return http.NewFileTransport(siteDir{fs: http.Dir(dir)})
+}
+
+// Open opens the file served for the given path.
+func (d siteDir) Open(name string) (http.File, error) {
+	f, err := d.fs.Open(name)
+	if errors.Is(err, fs.ErrNotExist) && name != "/" {
+		return d.fs.Open(name + cleanURLExtension)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return f, nil
+	}
+
+	// directories are not listed.
+	index, err := d.fs.Open(path.Join(name, indexFile))
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	_ = index.Close()
+
+	return f, nil
+}
@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPClient_Dir(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"index.html":      `<a href="/about">About</a>`,
+		"about.html":      `About`,
+		"docs/index.html": `Docs`,
+		"empty/style.css": `body {}`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	tests := []struct {
+		name              string
+		path              string
+		expectedCode      int
+		expectedBody      string
+		expectedRedirects []redirect
+	}{
+		{name: "index", path: "/", expectedCode: http.StatusOK, expectedBody: files["index.html"]},
+		{name: "file", path: "/about.html", expectedCode: http.StatusOK, expectedBody: files["about.html"]},
+		{name: "clean URL", path: "/about", expectedCode: http.StatusOK, expectedBody: files["about.html"]},
+		{name: "directory index", path: "/docs/", expectedCode: http.StatusOK, expectedBody: files["docs/index.html"]},
+		{
+			name:              "directory without trailing slash",
+			path:              "/docs",
+			expectedCode:      http.StatusOK,
+			expectedBody:      files["docs/index.html"],
+			expectedRedirects: []redirect{{Code: http.StatusMovedPermanently, Location: defaultDirHost + "/docs/"}},
+		},
+		{name: "directory without index", path: "/empty/", expectedCode: http.StatusNotFound},
+		{name: "missing file", path: "/missing", expectedCode: http.StatusNotFound},
+		{name: "path outside directory", path: "/../" + filepath.Base(dir) + "/about.html", expectedCode: http.StatusNotFound},
+	}
+
+	client, err := newHTTPClient(&inspectorConfig{Host: defaultDirHost, Dir: dir}, false)
+	require.NoError(t, err)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var redirects []redirect
+			req, err := http.NewRequestWithContext(
+				withRedirects(context.Background(), &redirects),
+				http.MethodGet,
+				defaultDirHost+test.path,
+				http.NoBody,
+			)
+			require.NoError(t, err)
+
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			require.Equal(t, test.expectedCode, resp.StatusCode)
+			require.Equal(t, test.expectedRedirects, redirects)
+
+			if test.expectedBody != "" {
+				b, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				require.Equal(t, test.expectedBody, string(b))
+			}
+		})
+	}
+}
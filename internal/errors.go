@@ -3,8 +3,34 @@ package internal
 import "github.com/ygrebnov/errorc"
 
 var (
-	ErrInvalidConfigurationSettings    = errorc.New("invalid configuration settings")
-	ErrInvalidPrinterOutputFormatValue = errorc.New("invalid printer.outputFormat value")
-	ErrEmptyHostValue                  = errorc.New("empty host value")
-	ErrInvalidHostValue                = errorc.New("invalid host value")
+	ErrInvalidConfigurationSettings           = errorc.New("invalid configuration settings")
+	ErrInvalidPrinterOutputFormatValue        = errorc.New("invalid printer.outputFormat value")
+	ErrEmptyHostValue                         = errorc.New("empty host value")
+	ErrInvalidHostValue                       = errorc.New("invalid host value")
+	ErrInvalidInspectorExtractorValue         = errorc.New("invalid inspector.extractors value")
+	ErrInvalidInspectorFailOnValue            = errorc.New("invalid inspector.failOn value")
+	ErrInvalidInspectorIncludeValue           = errorc.New("invalid inspector.include value")
+	ErrInvalidInspectorExcludeValue           = errorc.New("invalid inspector.exclude value")
+	ErrInvalidInspectorRequestsPerSecondValue = errorc.New("invalid inspector.requestsPerSecond value")
+	ErrInvalidInspectorRetryDelayValue        = errorc.New("invalid inspector.retryDelay value")
+	ErrInvalidInspectorRetryMaxDelayValue     = errorc.New("invalid inspector.retryMaxDelay value")
+	ErrInvalidInspectorAuthValue              = errorc.New("invalid inspector.auth value")
+	ErrInvalidInspectorCookiesValue           = errorc.New("invalid inspector.cookies value")
+	ErrInvalidInspectorLoginValue             = errorc.New("invalid inspector.login value")
+	ErrInvalidInspectorTLSValue               = errorc.New("invalid inspector.tls value")
+	ErrInvalidInspectorResolveValue           = errorc.New("invalid inspector.resolve value")
+	ErrInvalidInspectorProxyValue             = errorc.New("invalid inspector.proxy value")
+	ErrInvalidInspectorExternalProxyValue     = errorc.New("invalid inspector.externalProxy value")
+	ErrInvalidInspectorInternalHostsValue     = errorc.New("invalid inspector.internalHosts value")
+	ErrInvalidInspectorStartURLsValue         = errorc.New("invalid inspector.startURLs value")
+	ErrInvalidInspectorStartURLsFileValue     = errorc.New("invalid inspector.startURLsFile value")
+	ErrInvalidInspectorDirValue               = errorc.New("invalid inspector.dir value")
+	ErrInvalidCAFile                          = errorc.New("invalid CA file")
+	ErrInvalidCookiesFile                     = errorc.New("invalid cookies file")
+	ErrLoginFailed                            = errorc.New("login failed")
+	ErrCannotResolveSecretValue               = errorc.New("cannot resolve secret value")
+	ErrTooManyRedirects                       = errorc.New("too many redirects")
+	ErrInspectionFailed                       = errorc.New("inspection failed")
+	ErrNoURLsToCheck                          = errorc.New("no URLs to check")
+	ErrCheckHostRequired                      = errorc.New("host is required for checking with inspected host session")
 )
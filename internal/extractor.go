@@ -0,0 +1,301 @@
+package internal
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// defaultExtractors is a set of extractors used when none are configured.
+var defaultExtractors = []string{
+	"a@href",
+	"area@href",
+	"iframe@src",
+	"frame@src",
+	"link@href",
+	"img@src",
+	"img@srcset",
+	"script@src",
+	"source@src",
+	"source@srcset",
+	"video@src",
+	"video@poster",
+	"audio@src",
+	"track@src",
+	"embed@src",
+	"object@data",
+	"form@action",
+}
+
+// pageElements are elements referencing pages, which are inspected for further links.
+// Other elements reference resources, which are only checked.
+var pageElements = map[atom.Atom]struct{}{
+	atom.A:      {},
+	atom.Area:   {},
+	atom.Iframe: {},
+	atom.Frame:  {},
+}
+
+// skippedLinkRels are <link> element rel values not referencing resources.
+var skippedLinkRels = map[string]struct{}{
+	"preconnect":   {},
+	"dns-prefetch": {},
+}
+
+// pageLinkRels are <link> element rel values referencing pages, like translations or pagination ones.
+var pageLinkRels = map[string]struct{}{
+	"alternate": {},
+	"canonical": {},
+	"next":      {},
+	"prev":      {},
+}
+
+// extractors maps element names to names of their attributes holding links.
+type extractors map[string][]string
+
+// newExtractors parses extractors in "element@attribute" format.
+func newExtractors(values []string) (extractors, error) {
+	if len(values) == 0 {
+		values = defaultExtractors
+	}
+
+	e := make(extractors, len(values))
+	for _, v := range values {
+		element, attribute, ok := strings.Cut(strings.ToLower(strings.TrimSpace(v)), "@")
+		if !ok || element == "" || attribute == "" {
+			return nil, ErrInvalidInspectorExtractorValue
+		}
+
+		e[element] = append(e[element], attribute)
+	}
+
+	return e, nil
+}
+
+// getLinks returns links found in the given document.
+// Links are resolved against the document base URL, which defaults to the given base.
+// Links, which cannot be resolved, are returned as is.
+func (e extractors) getLinks(pageURL string, base *url.URL, doc *html.Node) []foundLink {
+	base = getBaseURL(base, doc)
+
+	res := make([]foundLink, 0)
+	for n := range doc.Descendants() {
+		if n.Type != html.ElementNode {
+			continue
+		}
+
+		attributes, ok := e[n.Data]
+		if !ok || isSkippedLinkRel(n) {
+			continue
+		}
+
+		isPage := isPageElement(n)
+		ref := &referrer{URL: pageURL, Text: getText(n), Element: n.Data}
+
+		for _, a := range n.Attr {
+			if !containsAttribute(attributes, a.Key) {
+				continue
+			}
+
+			for _, href := range getAttributeLinks(a) {
+				if u, err := base.Parse(href); err == nil {
+					href = u.String()
+				}
+
+				res = append(res, foundLink{href: href, ref: ref, resource: !isPage})
+			}
+		}
+	}
+
+	return res
+}
+
+// getAnchors returns anchors, which can be referenced by fragments, found in the given document.
+// Anchors are elements id attributes values and <a> elements name attributes values.
+func getAnchors(doc *html.Node) map[string]struct{} {
+	anchors := make(map[string]struct{})
+	for n := range doc.Descendants() {
+		if n.Type != html.ElementNode {
+			continue
+		}
+
+		for _, a := range n.Attr {
+			if a.Key == "id" || (a.Key == "name" && n.DataAtom == atom.A) {
+				anchors[a.Val] = struct{}{}
+			}
+		}
+	}
+
+	return anchors
+}
+
+// getBaseURL returns the URL specified by the first <base> element href attribute in the document,
+// resolved against the given URL. In case there is no such element, the given URL is returned.
+func getBaseURL(u *url.URL, doc *html.Node) *url.URL {
+	for n := range doc.Descendants() {
+		if n.Type != html.ElementNode || n.DataAtom != atom.Base {
+			continue
+		}
+
+		for _, a := range n.Attr {
+			if a.Key != "href" {
+				continue
+			}
+
+			if base, err := u.Parse(strings.TrimSpace(a.Val)); err == nil {
+				return base
+			}
+
+			return u
+		}
+	}
+
+	return u
+}
+
+// getAttributeLinks returns links held by the given attribute.
+// Empty links and data URLs are skipped.
+func getAttributeLinks(a html.Attribute) []string {
+	values := []string{a.Val}
+	if a.Key == "srcset" {
+		values = parseSrcset(a.Val)
+	}
+
+	res := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || strings.HasPrefix(strings.ToLower(v), "data:") {
+			continue
+		}
+
+		res = append(res, v)
+	}
+
+	return res
+}
+
+// parseSrcset returns image candidates URLs of the srcset attribute value, as per the HTML specification.
+// Candidate URL runs up to whitespace, so it may contain commas, like "/w_100,h_100/img.png".
+// It is followed by an optional descriptor and a comma.
+func parseSrcset(v string) []string {
+	var urls []string
+
+	for {
+		v = strings.TrimLeft(v, " \t\n\f\r,")
+		if v == "" {
+			return urls
+		}
+
+		end := strings.IndexAny(v, " \t\n\f\r")
+		if end < 0 {
+			end = len(v)
+		}
+
+		u := v[:end]
+		v = v[end:]
+
+		// URL ending with commas has no descriptor.
+		if trimmed := strings.TrimRight(u, ","); trimmed != u {
+			urls = append(urls, trimmed)
+			continue
+		}
+
+		urls = append(urls, u)
+		v = skipSrcsetDescriptor(v)
+	}
+}
+
+// skipSrcsetDescriptor returns the srcset value remaining after the candidate descriptor.
+// Commas within parentheses do not end the descriptor.
+func skipSrcsetDescriptor(v string) string {
+	parens := false
+
+	for i, r := range v {
+		switch {
+		case r == '(':
+			parens = true
+
+		case r == ')':
+			parens = false
+
+		case r == ',' && !parens:
+			return v[i+1:]
+		}
+	}
+
+	return ""
+}
+
+func containsAttribute(attributes []string, key string) bool {
+	for _, a := range attributes {
+		if a == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSkippedLinkRel checks whether the node is a <link> element with any of rel link types not referencing a resource.
+func isSkippedLinkRel(n *html.Node) bool {
+	return hasLinkRel(n, skippedLinkRels)
+}
+
+// isPageElement checks whether the node references a page, which is inspected for further links.
+func isPageElement(n *html.Node) bool {
+	_, isPage := pageElements[n.DataAtom]
+
+	return isPage || hasLinkRel(n, pageLinkRels)
+}
+
+// hasLinkRel checks whether the node is a <link> element with any of the given rel link types.
+func hasLinkRel(n *html.Node, rels map[string]struct{}) bool {
+	if n.DataAtom != atom.Link {
+		return false
+	}
+
+	for _, a := range n.Attr {
+		if a.Key != "rel" {
+			continue
+		}
+
+		// rel value is a space separated list of link types.
+		for _, rel := range strings.Fields(strings.ToLower(a.Val)) {
+			if _, ok := rels[rel]; ok {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return false
+}
+
+// getText returns the node text content with collapsed whitespace.
+// For elements without text content, alt or title attribute value is returned.
+func getText(n *html.Node) string {
+	var b strings.Builder
+	for d := range n.Descendants() {
+		if d.Type == html.TextNode {
+			b.WriteString(d.Data)
+			b.WriteByte(' ')
+		}
+	}
+
+	if text := strings.Join(strings.Fields(b.String()), " "); text != "" {
+		return text
+	}
+
+	for _, key := range []string{"alt", "title"} {
+		for _, a := range n.Attr {
+			if a.Key == key {
+				return strings.Join(strings.Fields(a.Val), " ")
+			}
+		}
+	}
+
+	return ""
+}
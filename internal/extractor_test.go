@@ -0,0 +1,183 @@
+package internal
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+)
+
+func TestGetAnchors(t *testing.T) {
+	doc, err := html.Parse(
+		strings.NewReader(
+			`<h1 id="title">Title</h1>
+<a name="named">Named</a>
+<div name="not-anchor"><span id="nested"></span></div>`,
+		),
+	)
+	require.NoError(t, err)
+
+	require.Equal(
+		t,
+		map[string]struct{}{"title": {}, "named": {}, "nested": {}},
+		getAnchors(doc),
+	)
+}
+
+func TestExtractors_GetLinks(t *testing.T) {
+	tests := []struct {
+		name        string
+		extractors  []string
+		html        string
+		expected    []foundLink
+		expectedErr error
+	}{
+		{
+			name: "default",
+			html: `<html><head>
+<link rel="stylesheet" href="/style.css">
+<link rel="preconnect" href="https://fonts.example.com">
+<script src="/app.js"></script>
+</head><body>
+<a href="/page">Page</a>
+<img src="/img.png" alt="Image" srcset="/img-1x.png 1x, /img-2x.png 2x">
+<img src="data:image/png;base64,AAAA">
+<iframe src="/frame"></iframe>
+<video poster="/poster.jpg"><source src="/video.mp4"></video>
+<form action="/submit"></form>
+</body></html>`,
+			expected: []foundLink{
+				{href: "http://host/style.css", ref: &referrer{URL: "http://host/start", Element: "link"}, resource: true},
+				{href: "http://host/app.js", ref: &referrer{URL: "http://host/start", Element: "script"}, resource: true},
+				{href: "http://host/page", ref: &referrer{URL: "http://host/start", Text: "Page", Element: "a"}},
+				{href: "http://host/img.png", ref: &referrer{URL: "http://host/start", Text: "Image", Element: "img"}, resource: true},
+				{href: "http://host/img-1x.png", ref: &referrer{URL: "http://host/start", Text: "Image", Element: "img"}, resource: true},
+				{href: "http://host/img-2x.png", ref: &referrer{URL: "http://host/start", Text: "Image", Element: "img"}, resource: true},
+				{href: "http://host/frame", ref: &referrer{URL: "http://host/start", Element: "iframe"}},
+				{href: "http://host/poster.jpg", ref: &referrer{URL: "http://host/start", Element: "video"}, resource: true},
+				{href: "http://host/video.mp4", ref: &referrer{URL: "http://host/start", Element: "source"}, resource: true},
+				{href: "http://host/submit", ref: &referrer{URL: "http://host/start", Element: "form"}, resource: true},
+			},
+		},
+
+		{
+			name:       "configured",
+			extractors: []string{"a@href", "IMG@src"},
+			html: `<a href="/page">Page</a>
+<img src="/img.png" srcset="/img-2x.png 2x">
+<script src="/app.js"></script>`,
+			expected: []foundLink{
+				{href: "http://host/page", ref: &referrer{URL: "http://host/start", Text: "Page", Element: "a"}},
+				{href: "http://host/img.png", ref: &referrer{URL: "http://host/start", Element: "img"}, resource: true},
+			},
+		},
+
+		{
+			name: "relative links",
+			html: `<a href="nested">Nested</a>
+<a href="../up">Up</a>
+<a href="#section">Section</a>
+<a href="https://other.host/page">Other</a>`,
+			expected: []foundLink{
+				{href: "http://host/docs/guide/nested", ref: &referrer{URL: "http://host/start", Text: "Nested", Element: "a"}},
+				{href: "http://host/docs/up", ref: &referrer{URL: "http://host/start", Text: "Up", Element: "a"}},
+				{href: "http://host/docs/guide/#section", ref: &referrer{URL: "http://host/start", Text: "Section", Element: "a"}},
+				{href: "https://other.host/page", ref: &referrer{URL: "http://host/start", Text: "Other", Element: "a"}},
+			},
+		},
+
+		{
+			name: "base element",
+			html: `<html><head><base href="/other/"></head><body>
+<a href="nested">Nested</a>
+<a href="/absolute">Absolute</a>
+</body></html>`,
+			expected: []foundLink{
+				{href: "http://host/other/nested", ref: &referrer{URL: "http://host/start", Text: "Nested", Element: "a"}},
+				{href: "http://host/absolute", ref: &referrer{URL: "http://host/start", Text: "Absolute", Element: "a"}},
+			},
+		},
+
+		{
+			name: "srcset with commas and rel link types",
+			html: `<link rel="dns-prefetch preconnect" href="https://cdn.example.com">
+<link rel="preload stylesheet" href="/style.css">
+<img srcset="/w_100,h_100/a.jpg 1x,/w_200,h_200/a.jpg 2x">`,
+			expected: []foundLink{
+				{href: "http://host/style.css", ref: &referrer{URL: "http://host/start", Element: "link"}, resource: true},
+				{href: "http://host/w_100,h_100/a.jpg", ref: &referrer{URL: "http://host/start", Element: "img"}, resource: true},
+				{href: "http://host/w_200,h_200/a.jpg", ref: &referrer{URL: "http://host/start", Element: "img"}, resource: true},
+			},
+		},
+
+		{
+			name: "page link types",
+			html: `<link rel="alternate" hreflang="de" href="/de/">
+<link rel="canonical" href="/start">
+<link rel="next" href="/start?page=2">
+<link rel="icon" href="/favicon.ico">`,
+			expected: []foundLink{
+				{href: "http://host/de/", ref: &referrer{URL: "http://host/start", Element: "link"}},
+				{href: "http://host/start", ref: &referrer{URL: "http://host/start", Element: "link"}},
+				{href: "http://host/start?page=2", ref: &referrer{URL: "http://host/start", Element: "link"}},
+				{href: "http://host/favicon.ico", ref: &referrer{URL: "http://host/start", Element: "link"}, resource: true},
+			},
+		},
+
+		{
+			name: "invalid link",
+			html: `<a href="--://invalid">Invalid</a>`,
+			expected: []foundLink{
+				{href: "--://invalid", ref: &referrer{URL: "http://host/start", Text: "Invalid", Element: "a"}},
+			},
+		},
+
+		{
+			name:        "invalid",
+			extractors:  []string{"a@href", "img"},
+			expectedErr: ErrInvalidInspectorExtractorValue,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := newExtractors(test.extractors)
+			if test.expectedErr != nil {
+				require.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			doc, err := html.Parse(strings.NewReader(test.html))
+			require.NoError(t, err)
+
+			base, err := url.Parse("http://host/docs/guide/")
+			require.NoError(t, err)
+
+			require.Equal(t, test.expected, e.getLinks("http://host/start", base, doc))
+		})
+	}
+}
+
+func TestParseSrcset(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []string
+	}{
+		{"/a.png", []string{"/a.png"}},
+		{"/a.png 1x, /b.png 2x", []string{"/a.png", "/b.png"}},
+		{"/a.png 100w,/b.png 200w", []string{"/a.png", "/b.png"}},
+		{"/w_100,h_100/a.jpg 1x, /w_200,h_200/a.jpg 2x", []string{"/w_100,h_100/a.jpg", "/w_200,h_200/a.jpg"}},
+		{"/a.png,, /b.png", []string{"/a.png", "/b.png"}},
+		{" \n/a.png 1x ,\n /b.png (max-width: 1px, 2px) 2x, /c.png", []string{"/a.png", "/b.png", "/c.png"}},
+		{"", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			require.Equal(t, test.expected, parseSrcset(test.value))
+		})
+	}
+}
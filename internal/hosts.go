@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/ygrebnov/errorc"
+)
+
+// hostPattern matches URL hosts regardless of the URL scheme.
+// Wildcard pattern, like "*.example.com", matches any subdomain, but not the domain itself.
+// Pattern without port matches any port.
+type hostPattern struct {
+	hostname string
+	port     string
+	wildcard bool
+}
+
+// newHostPatterns parses inspector.internalHosts values.
+// Values may contain a scheme, which is ignored.
+func newHostPatterns(values []string) ([]hostPattern, error) {
+	patterns := make([]hostPattern, 0, len(values))
+
+	for _, v := range values {
+		host := v
+		if _, rest, found := strings.Cut(host, "://"); found {
+			host = rest
+		}
+		host = strings.TrimSuffix(host, "/")
+
+		p := hostPattern{}
+		if rest, found := strings.CutPrefix(host, "*."); found {
+			p.wildcard = true
+			host = rest
+		}
+
+		u, err := url.Parse("//" + host)
+		if err != nil || u.Host != host || u.Hostname() == "" || strings.Contains(u.Hostname(), "*") {
+			return nil, errorc.With(ErrInvalidInspectorInternalHostsValue, errorc.Field("value", v))
+		}
+
+		p.hostname, p.port = strings.ToLower(u.Hostname()), u.Port()
+		patterns = append(patterns, p)
+	}
+
+	return patterns, nil
+}
+
+// matches checks whether the URL host matches the pattern.
+func (p hostPattern) matches(u *url.URL) bool {
+	if p.port != "" && p.port != getPort(u) {
+		return false
+	}
+
+	hostname := strings.ToLower(u.Hostname())
+	if p.wildcard {
+		return strings.HasSuffix(hostname, "."+p.hostname)
+	}
+
+	return hostname == p.hostname
+}
+
+// getPort returns the URL port or the default port of the URL scheme.
+func getPort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+
+	switch u.Scheme {
+	case "http":
+		return "80"
+
+	case "https":
+		return "443"
+	}
+
+	return ""
+}
+
+// isInternal checks whether the URL matches the inspected host or one of inspector.internalHosts.
+// Internal URLs are inspected, other ones are external links.
+func (i *defaultInspector) isInternal(u *url.URL) bool {
+	for _, p := range i.internalHosts {
+		if p.matches(u) {
+			return true
+		}
+	}
+
+	return false
+}
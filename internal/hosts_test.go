@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostPattern(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		url      string
+		expected bool
+	}{
+		{"example.com", "http://example.com/page", true},
+		{"example.com", "https://EXAMPLE.com:8443/page", true},
+		{"https://example.com/", "http://example.com/page", true},
+		{"example.com", "http://www.example.com/page", false},
+		{"*.example.com", "http://www.example.com/page", true},
+		{"*.example.com", "https://docs.eu.example.com/page", true},
+		{"*.example.com", "http://example.com/page", false},
+		{"*.example.com", "http://badexample.com/page", false},
+		{"example.com:8080", "http://example.com:8080/page", true},
+		{"example.com:8080", "http://example.com/page", false},
+		{"example.com:443", "https://example.com/page", true},
+		{"[::1]:8080", "http://[::1]:8080/page", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern+" "+test.url, func(t *testing.T) {
+			patterns, err := newHostPatterns([]string{test.pattern})
+			require.NoError(t, err)
+
+			u, err := url.Parse(test.url)
+			require.NoError(t, err)
+
+			require.Equal(t, test.expected, patterns[0].matches(u))
+		})
+	}
+}
+
+func TestNewHostPatterns_Invalid(t *testing.T) {
+	for _, v := range []string{"", "*.", "example.com/docs", "www.*.example.com", "example.com:port"} {
+		t.Run(v, func(t *testing.T) {
+			_, err := newHostPatterns([]string{v})
+			require.ErrorIs(t, err, ErrInvalidInspectorInternalHostsValue)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"time"
 
 	"golang.org/x/net/html"
 )
@@ -18,6 +19,8 @@ type injectables struct {
 	templateParseFiles func(fs.FS, string) (htmlTemplate, error)
 	htmlParse          func(io.Reader) (*html.Node, error)
 	printFn            func(a ...any) (n int, err error)
+	errPrintFn         func(a ...any) (n int, err error)
+	now                func() time.Time
 }
 
 // getUserConfigDir returns the userConfigDir dependency or the default implementation.
@@ -75,3 +78,23 @@ func (i *injectables) getPrintFn() func(a ...any) (n int, err error) {
 
 	return fmt.Println
 }
+
+// getErrPrintFn returns the errPrintFn dependency or the default implementation printing to stderr.
+func (i *injectables) getErrPrintFn() func(a ...any) (n int, err error) {
+	if i.errPrintFn != nil {
+		return i.errPrintFn
+	}
+
+	return func(a ...any) (int, error) {
+		return fmt.Fprintln(os.Stderr, a...)
+	}
+}
+
+// getNow returns the now dependency or the default implementation.
+func (i *injectables) getNow() func() time.Time {
+	if i.now != nil {
+		return i.now
+	}
+
+	return time.Now
+}
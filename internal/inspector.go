@@ -4,33 +4,68 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/ygrebnov/workers"
 	"golang.org/x/net/html"
-	"golang.org/x/net/html/atom"
 )
 
 type inspector interface {
-	inspect(ctx context.Context, startPath string, done chan<- struct{})
+	inspect(ctx context.Context, startURLs []string, done chan<- struct{})
+	check(ctx context.Context, urls []string, done chan<- struct{})
+}
+
+// foundLink is a link found on an inspected page.
+// Resource links are checked, but not inspected for further links.
+// Depth is the number of pages between the start page and the link, the start link is not limited by scope.
+// Site is the origin of the start URL, from which the link has been found.
+type foundLink struct {
+	href     string
+	ref      *referrer
+	site     string
+	resource bool
+	start    bool
+	depth    uint
+}
+
+// page holds links and anchors found on an inspected page.
+type page struct {
+	url     string
+	site    string
+	links   []foundLink
+	anchors map[string]struct{}
+	depth   uint
 }
 
 type defaultInspector struct {
 	cfg           *inspectorConfig
 	baseURL       *url.URL
 	excludedCodes map[int]struct{}
+	extractors    extractors
+	scope         *scope
+	internalHosts []hostPattern // the inspected host is the first one.
+	pages         atomic.Uint64 // number of requested pages.
 
-	htmlProvider workers.Workers[*link]
-	htmlParser   workers.Workers[[]string]
-	httpClient   httpClient
+	htmlProvider       workers.Workers[*link]
+	externalProvider   workers.Workers[*link]
+	htmlParser         workers.Workers[*page]
+	httpClient         httpClient
+	externalHTTPClient httpClient
 
 	visitedURLs *sync.Map
+	anchors     sync.Map // inspected page URL -> page anchors.
+	fragments   sync.Map // link URL with fragment -> *link.
+	sitemapURLs sync.Map // page URL listed in sitemap -> struct{}.
+
+	robots  sync.Map // scheme and host -> *hostRobots.
+	limiter *hostLimiter
 
 	toPrint chan<- *link
 
@@ -41,7 +76,7 @@ type defaultInspector struct {
 
 func newInspector(
 	cfg *inspectorConfig,
-	httpClient httpClient,
+	httpClient, externalHTTPClient httpClient,
 	visitedURLs *sync.Map,
 	toPrint chan<- *link,
 	deps injectables,
@@ -51,37 +86,101 @@ func newInspector(
 		return nil, err
 	}
 
+	e, err := newExtractors(cfg.Extractors)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := newScope(cfg.Include, cfg.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	internalHosts, err := newHostPatterns(append([]string{baseURL.Host}, cfg.InternalHosts...))
+	if err != nil {
+		return nil, err
+	}
+
 	excludedCodes := make(map[int]struct{}, len(cfg.SkipStatusCodes))
 	for _, code := range cfg.SkipStatusCodes {
 		excludedCodes[code] = struct{}{}
 	}
 
 	return &defaultInspector{
-		cfg:           cfg,
-		baseURL:       baseURL,
-		excludedCodes: excludedCodes,
-		httpClient:    httpClient,
-		visitedURLs:   visitedURLs,
-		toPrint:       toPrint,
-		wg:            sync.WaitGroup{},
-		deps:          deps,
+		cfg:                cfg,
+		baseURL:            baseURL,
+		excludedCodes:      excludedCodes,
+		extractors:         e,
+		scope:              s,
+		internalHosts:      internalHosts,
+		limiter:            newHostLimiter(cfg.RequestsPerSecond, deps.getNow()),
+		httpClient:         httpClient,
+		externalHTTPClient: externalHTTPClient,
+		visitedURLs:        visitedURLs,
+		toPrint:            toPrint,
+		wg:                 sync.WaitGroup{},
+		deps:               deps,
 	}, nil
 }
 
-func (i *defaultInspector) inspect(ctx context.Context, startPath string, done chan<- struct{}) {
+// inspect crawls the sites starting from the given URLs, which may be relative to the inspected host.
+// Start URLs hosts are internal.
+func (i *defaultInspector) inspect(ctx context.Context, startURLs []string, done chan<- struct{}) {
+	if len(startURLs) == 0 {
+		startURLs = []string{defaultStartPath}
+	}
+
+	for _, s := range startURLs {
+		if u, err := i.baseURL.Parse(s); err == nil && !i.isInternal(u) {
+			p := hostPattern{hostname: strings.ToLower(u.Hostname()), port: u.Port()}
+			i.internalHosts = append(i.internalHosts, p)
+		}
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 
-	i.htmlProvider = workers.New[*link](ctx, &workers.Config{MaxWorkers: uint(runtime.NumCPU()), StartImmediately: true})
-	i.htmlParser = workers.New[[]string](ctx, &workers.Config{MaxWorkers: uint(runtime.NumCPU()), StartImmediately: true})
+	i.htmlProvider = workers.New[*link](
+		ctx,
+		&workers.Config{MaxWorkers: getMaxWorkers(i.cfg.Concurrency), StartImmediately: true},
+	)
+	i.htmlParser = workers.New[*page](
+		ctx,
+		&workers.Config{MaxWorkers: getMaxWorkers(i.cfg.ParserConcurrency), StartImmediately: true},
+	)
 
 	go i.parseHTML(ctx)
-	go i.provideHTML(ctx)
+	go i.provideHTML(ctx, i.htmlProvider)
+
+	if i.cfg.CheckExternalLinks {
+		i.externalProvider = workers.New[*link](
+			ctx,
+			&workers.Config{MaxWorkers: getMaxWorkers(i.cfg.ExternalConcurrency), StartImmediately: true},
+		)
+
+		go i.provideHTML(ctx, i.externalProvider)
+	}
+
+	for _, s := range startURLs {
+		site := getOrigin(i.baseURL)
+		if u, err := i.baseURL.Parse(s); err == nil {
+			site = getOrigin(u)
+		}
 
-	i.wg.Add(1)
-	_ = i.htmlProvider.AddTask(i.newGetHTMLTask(startPath))
+		i.wg.Add(1)
+		_ = i.htmlProvider.AddTask(i.newGetHTMLTask(&foundLink{href: s, site: site, start: true}))
+	}
+
+	if i.cfg.Sitemap != "" {
+		i.wg.Add(1)
+		go i.seedFromSitemap(ctx)
+	}
 
 	i.wg.Wait()
 	cancel()
+
+	i.checkAnchors()
+	i.checkSitemap(startURLs)
+
 	done <- struct{}{}
 }
 
@@ -93,13 +192,18 @@ func (i *defaultInspector) parseHTML(ctx context.Context) {
 			return
 
 		case e := <-i.htmlParser.GetErrors():
-			_, _ = i.deps.getPrintFn()(fmt.Errorf("error parsing page content: %w", e))
+			_, _ = i.deps.getErrPrintFn()(fmt.Errorf("error parsing page content: %w", e))
 			i.wg.Done()
 
-		case paths := <-i.htmlParser.GetResults():
-			for _, path := range paths {
+		case p := <-i.htmlParser.GetResults():
+			i.anchors.Store(p.url, p.anchors)
+
+			for _, fl := range p.links {
+				fl.depth = p.depth + 1
+				fl.site = p.site
+
 				i.wg.Add(1)
-				_ = i.htmlProvider.AddTask(i.newGetHTMLTask(path))
+				_ = i.htmlProvider.AddTask(i.newGetHTMLTask(&fl))
 			}
 
 			i.wg.Done()
@@ -107,18 +211,18 @@ func (i *defaultInspector) parseHTML(ctx context.Context) {
 	}
 }
 
-// provideHTML controls HTML provision flow.
-func (i *defaultInspector) provideHTML(ctx context.Context) {
+// provideHTML controls HTML provision flow of the given provider.
+func (i *defaultInspector) provideHTML(ctx context.Context, provider workers.Workers[*link]) {
 	for {
 		select {
 		case <-ctx.Done():
 			return
 
-		case e := <-i.htmlProvider.GetErrors():
-			_, _ = i.deps.getPrintFn()(fmt.Errorf("error doing http request: %w", e))
+		case e := <-provider.GetErrors():
+			_, _ = i.deps.getErrPrintFn()(fmt.Errorf("error doing http request: %w", e))
 			i.wg.Done()
 
-		case l := <-i.htmlProvider.GetResults():
+		case l := <-provider.GetResults():
 			if l == nil {
 				i.wg.Done()
 				break
@@ -133,9 +237,10 @@ func (i *defaultInspector) provideHTML(ctx context.Context) {
 
 			i.toPrint <- l
 
-			if l.code < http.StatusBadRequest {
+			if l.code < http.StatusBadRequest && !l.external && !l.resource &&
+				(i.cfg.MaxDepth == 0 || l.depth < i.cfg.MaxDepth) {
 				i.wg.Add(1)
-				_ = i.htmlParser.AddTask(i.newGetLinksTask(l.body))
+				_ = i.htmlParser.AddTask(i.newGetLinksTask(l))
 			}
 
 			i.wg.Done()
@@ -143,60 +248,253 @@ func (i *defaultInspector) provideHTML(ctx context.Context) {
 	}
 }
 
-func (i *defaultInspector) newGetHTMLTask(path string) func(ctx context.Context) *link {
+// newGetHTMLTask creates a task requesting the given link.
+// Link referrer is nil for the start path.
+func (i *defaultInspector) newGetHTMLTask(fl *foundLink) func(ctx context.Context) *link {
 	return func(ctx context.Context) *link {
-		u, err := i.baseURL.Parse(path)
-		switch {
-		case err != nil:
-			return i.store(&link{URL: path, code: statusError})
+		ref := fl.ref
 
-		case u.Host != i.baseURL.Host && i.cfg.LogExternalLinks:
-			return i.store(&link{URL: u.String(), code: statusExternalLink})
+		u, err := i.baseURL.Parse(fl.href)
+		if err != nil {
+			return i.store(&link{URL: fl.href, code: statusError, err: err}, ref, fl.site)
+		}
 
-		case u.Host != i.baseURL.Host:
-			return nil // skip external link.
+		// links with other schemes, like mailto: or tel:, cannot be requested.
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil
+		}
+
+		if u.Fragment != "" {
+			if i.isInternal(u) {
+				i.addFragment(u.String(), ref, fl.site)
+			}
+
+			u.Fragment, u.RawFragment = "", ""
+
+			// links to the referring page fragments are only checked against its anchors.
+			if ref != nil && u.String() == ref.URL {
+				return nil
+			}
 		}
 
 		existingLink, exists := i.visitedURLs.Load(u.String())
 		if exists {
-			existingLink.(*link).Occurrences++
+			existingLink.(*link).addOccurrence(ref)
 			return nil
 		}
 
-		attempts := byte(0)
-		for attempts < i.cfg.RetryAttempts {
-			req, err1 := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
-			if err1 != nil {
-				return i.store(&link{URL: u.String(), code: statusError})
-			}
-			req.Header.Add("User-Agent", applicationName+"/"+version)
+		if !i.isInternal(u) {
+			return i.external(u.String(), fl)
+		}
 
-			resp, err2 := i.httpClient.Do(req)
-			switch {
-			case err2 != nil && errors.Is(err2, syscall.ECONNRESET):
-				select {
-				case <-ctx.Done():
-					// TODO: add a test case for this.
-					return i.store(&link{URL: u.String(), code: statusError})
+		if !fl.start && !i.scope.contains(u) {
+			return i.skip(u.String(), fl, ref)
+		}
 
-				case <-time.After(i.cfg.RetryDelay):
-					attempts++
-				}
+		if !i.isAllowedByRobots(ctx, u) {
+			return i.store(&link{URL: u.String(), code: statusRobotsDisallowed, resource: fl.resource}, ref, fl.site)
+		}
 
-			case err2 != nil:
-				return i.store(&link{URL: u.String(), code: statusError})
+		if !fl.resource && i.cfg.MaxPages > 0 && i.pages.Add(1) > uint64(i.cfg.MaxPages) {
+			return i.skip(u.String(), fl, ref)
+		}
 
-			default:
-				return i.store(&link{URL: u.String(), code: resp.StatusCode, body: resp.Body})
+		l := i.get(ctx, i.httpClient, u.String())
+		if fl.resource && l.body != nil {
+			_ = l.body.Close()
+			l.body = nil
+		}
+		l.resource = fl.resource
+		l.depth = fl.depth
+
+		return i.store(l, ref, fl.site)
+	}
+}
+
+// external checks, logs or skips the external link according to the configuration.
+func (i *defaultInspector) external(u string, fl *foundLink) *link {
+	switch {
+	case i.cfg.CheckExternalLinks:
+		// link is stored before requesting, so that links found on several pages are requested once.
+		l := i.store(&link{URL: u, external: true}, fl.ref, fl.site)
+		if l == nil {
+			return nil
+		}
+
+		i.wg.Add(1)
+		_ = i.externalProvider.AddTask(i.newGetExternalTask(l))
+		return nil
+
+	case i.cfg.LogExternalLinks:
+		return i.store(&link{URL: u, code: statusExternalLink}, fl.ref, fl.site)
+	}
+
+	return nil // skip external link.
+}
+
+// skip reports the link out of the inspection scope, if inspector.reportExcluded is set.
+func (i *defaultInspector) skip(u string, fl *foundLink, ref *referrer) *link {
+	if !i.cfg.ReportExcluded {
+		return nil
+	}
+
+	return i.store(&link{URL: u, code: statusOutOfScope, resource: fl.resource}, ref, fl.site)
+}
+
+// addFragment registers an occurrence of the link with fragment.
+// Fragments are checked against target page anchors once inspection is finished.
+func (i *defaultInspector) addFragment(u string, ref *referrer, site string) {
+	l := &link{URL: u, site: site}
+	if ref != nil {
+		l.Referrers = []referrer{*ref}
+	}
+
+	if existingLink, loaded := i.fragments.LoadOrStore(u, l); loaded {
+		existingLink.(*link).addOccurrence(ref)
+	}
+}
+
+// checkAnchors outputs links with fragments not matching any anchor on the target page.
+// Links to pages, which have not been inspected, are not checked.
+func (i *defaultInspector) checkAnchors() {
+	if _, excluded := i.excludedCodes[statusAnchorNotFound]; excluded {
+		return
+	}
+
+	i.fragments.Range(func(_, value any) bool {
+		l := value.(*link)
+
+		u, err := url.Parse(l.URL)
+		if err != nil || isImplicitAnchor(u.Fragment) {
+			return true
+		}
+
+		fragment := u.Fragment
+		u.Fragment, u.RawFragment = "", ""
+
+		anchors, inspected := i.anchors.Load(u.String())
+		if !inspected {
+			return true
+		}
+
+		if _, found := anchors.(map[string]struct{})[fragment]; found {
+			return true
+		}
+
+		l.code = statusAnchorNotFound
+		if l = i.store(l, nil, l.site); l != nil {
+			i.toPrint <- l
+		}
+
+		return true
+	})
+}
+
+// isImplicitAnchor checks whether the fragment references an anchor existing on every page.
+func isImplicitAnchor(fragment string) bool {
+	return strings.EqualFold(fragment, "top")
+}
+
+// newGetExternalTask creates a task checking the given stored external link.
+// External pages are not parsed.
+func (i *defaultInspector) newGetExternalTask(l *link) func(ctx context.Context) *link {
+	return func(ctx context.Context) *link {
+		res := i.get(ctx, i.externalHTTPClient, l.URL)
+		if res.body != nil {
+			_ = res.body.Close()
+		}
+
+		l.code, l.err, l.Redirects = res.code, res.err, res.Redirects
+
+		return l
+	}
+}
+
+// get requests the given URL using the given client and returns the resulting link.
+// Requests failing with transient network errors or inspector.retryStatusCodes are retried
+// up to inspector.retryAttempts times in total.
+func (i *defaultInspector) get(ctx context.Context, client httpClient, u string) *link {
+	for attempt := byte(0); ; attempt++ {
+		var redirects []redirect
+		req, err1 := http.NewRequestWithContext(withRedirects(ctx, &redirects), http.MethodGet, u, http.NoBody)
+		if err1 != nil {
+			return &link{URL: u, code: statusError, err: err1}
+		}
+		req.Header.Add("User-Agent", i.cfg.getUserAgent())
+
+		if err := i.limiter.wait(ctx, req.URL.Host); err != nil {
+			return &link{URL: u, code: statusError, err: err}
+		}
+
+		resp, err2 := client.Do(req)
+
+		var l *link
+
+		switch {
+		case isCertificateError(err2):
+			return &link{URL: u, code: statusCertificate, err: err2}
+
+		case err2 != nil:
+			l = &link{URL: u, code: statusError, err: err2}
+			if !isTransientError(err2) {
+				return l
 			}
+
+		case !i.isRetryableResponse(resp):
+			return &link{URL: u, code: resp.StatusCode, body: resp.Body, Redirects: redirects}
+
+		default:
+			l = &link{URL: u, code: resp.StatusCode, body: resp.Body, Redirects: redirects}
+		}
+
+		if attempt+1 >= i.cfg.RetryAttempts || ctx.Err() != nil {
+			return l
+		}
+
+		delay := i.getRetryDelay(attempt, resp)
+
+		if l.body != nil {
+			_ = l.body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return &link{URL: u, code: statusError, err: ctx.Err()}
+
+		case <-time.After(delay):
 		}
+	}
+}
 
-		return i.store(&link{URL: u.String(), code: statusError})
+// getUserAgent returns the configured user agent or the default one.
+func (c *inspectorConfig) getUserAgent() string {
+	if c.UserAgent != "" {
+		return c.UserAgent
 	}
+
+	return applicationName + "/" + version
+}
+
+// getMaxWorkers returns the given workers number or the number of CPUs, if it is zero.
+func getMaxWorkers(n uint) uint {
+	if n == 0 {
+		return uint(runtime.NumCPU())
+	}
+
+	return n
 }
 
-func (i *defaultInspector) store(l *link) *link {
-	if _, loaded := i.visitedURLs.LoadOrStore(l.URL, l); loaded {
+// store saves the link found from the given site into visited URLs and returns it.
+// In case the link has already been visited, its occurrence is registered on the stored link and nil is returned.
+func (i *defaultInspector) store(l *link, ref *referrer, site string) *link {
+	if ref != nil {
+		l.Referrers = []referrer{*ref}
+	}
+	l.site = site
+
+	if existingLink, loaded := i.visitedURLs.LoadOrStore(l.URL, l); loaded {
+		existingLink.(*link).addOccurrence(ref)
+
 		if l.body != nil {
 			_ = l.body.Close()
 		}
@@ -205,50 +503,47 @@ func (i *defaultInspector) store(l *link) *link {
 	return l
 }
 
-func (i *defaultInspector) newGetLinksTask(data io.ReadCloser) func(ctx context.Context) ([]string, error) {
-	// TODO: pass url to return in error.
-	return func(ctx context.Context) ([]string, error) {
-		defer data.Close()
+// newGetLinksTask creates a task extracting links from the given page body.
+func (i *defaultInspector) newGetLinksTask(l *link) func(ctx context.Context) (*page, error) {
+	return func(ctx context.Context) (*page, error) {
+		defer l.body.Close()
 
-		var (
-			doc *html.Node
-			err error
-		)
+		// links are resolved against the final page URL.
+		base, err := i.baseURL.Parse(l.getResponseURL())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", l.URL, err)
+		}
+
+		var doc *html.Node
 
 		attempts := byte(0)
 		for attempts < i.cfg.RetryAttempts {
-			doc, err = i.deps.getHTMLParse()(data)
+			doc, err = i.deps.getHTMLParse()(l.body)
 			switch {
 			case err != nil && errors.Is(err, syscall.ECONNRESET):
 				select {
 				case <-ctx.Done():
 					// TODO: add a test case for this.
-					return nil, err
+					return nil, fmt.Errorf("%s: %w", l.URL, err)
 
 				case <-time.After(i.cfg.RetryDelay):
 					attempts++
 				}
 
 			case err != nil:
-				return nil, err
+				return nil, fmt.Errorf("%s: %w", l.URL, err)
 
 			default:
-				res := make([]string, 0)
-				for n := range doc.Descendants() {
-					if n.Type == html.ElementNode && n.DataAtom == atom.A {
-						for _, a := range n.Attr {
-							if a.Key == "href" {
-								res = append(res, a.Val)
-								break
-							}
-						}
-					}
-				}
-
-				return res, nil
+				return &page{
+					url:     l.URL,
+					site:    l.site,
+					links:   i.extractors.getLinks(l.URL, base, doc),
+					anchors: getAnchors(doc),
+					depth:   l.depth,
+				}, nil
 			}
 		}
 
-		return nil, err
+		return nil, fmt.Errorf("%s: %w", l.URL, err)
 	}
 }
@@ -1,7 +1,11 @@
 package internal
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
@@ -36,6 +40,9 @@ func (c *mockHTTPClient) defaultDo(req *http.Request) (*http.Response, error) {
 
 	case req.URL.Path == "/connreset":
 		return nil, syscall.ECONNRESET
+
+	case req.URL.Path == "/certerror":
+		return nil, &tls.CertificateVerificationError{Err: x509.HostnameError{Host: req.URL.Host}}
 	}
 
 	r, ok := c.data[req.URL.String()]
@@ -68,6 +75,7 @@ func TestInspector(t *testing.T) {
 	tests := []struct {
 		name        string
 		cfg         *inspectorConfig
+		startURLs   []string
 		before      func(t *testing.T) injectables
 		httpClient  httpClient
 		expected    map[string]int
@@ -205,6 +213,448 @@ func TestInspector(t *testing.T) {
 			},
 		},
 
+		{
+			name: "check external links",
+			cfg: &inspectorConfig{
+				Host:               "http://host",
+				CheckExternalLinks: true,
+				RetryDelay:         10 * time.Millisecond,
+				RetryAttempts:      3,
+			},
+			httpClient: &mockHTTPClient{
+				data: map[string]*http.Response{
+					"http://host/start": {
+						StatusCode: http.StatusOK,
+						Body: io.NopCloser(
+							strings.NewReader(
+								`<p>Links:</p><ul>
+<li><a href="link1">Link1</a>
+<li><a href="http://other.host">Other host</a>
+<li><a href="http://other.host/missing">Other host missing</a>
+</ul>`,
+							),
+						),
+					},
+					"http://other.host": {
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`<a href="http://other.host/notchecked">Link</a>`)),
+					},
+				},
+				do: (*mockHTTPClient).defaultDo,
+			},
+			expected: map[string]int{
+				"http://host/start":         http.StatusOK,
+				"http://host/link1":         http.StatusNotFound,
+				"http://other.host":         http.StatusOK,
+				"http://other.host/missing": http.StatusNotFound,
+			},
+		},
+
+		{
+			name: "non-HTTP schemes",
+			cfg: &inspectorConfig{
+				Host:               "http://host",
+				CheckExternalLinks: true,
+				LogExternalLinks:   true,
+				RetryDelay:         10 * time.Millisecond,
+				RetryAttempts:      3,
+			},
+			httpClient: &mockHTTPClient{
+				data: map[string]*http.Response{
+					"http://host/start": {
+						StatusCode: http.StatusOK,
+						Body: io.NopCloser(
+							strings.NewReader(
+								`<a href="mailto:team@host">Mail</a>
+<a href="tel:+10000000000">Phone</a>
+<a href="javascript:void(0)">Menu</a>
+<a href="ftp://files.host/file">File</a>
+<a href="HTTPS://other.host/page">Other host</a>`,
+							),
+						),
+					},
+				},
+				do: (*mockHTTPClient).defaultDo,
+			},
+			expected: map[string]int{
+				"http://host/start":       http.StatusOK,
+				"https://other.host/page": http.StatusNotFound,
+			},
+		},
+
+		{
+			name: "resources",
+			cfg:  defaultConfig,
+			httpClient: &mockHTTPClient{
+				data: map[string]*http.Response{
+					"http://host/start": {
+						StatusCode: http.StatusOK,
+						Body: io.NopCloser(
+							strings.NewReader(
+								`<link rel="stylesheet" href="style.css">
+<img src="image.png" srcset="image-2x.png 2x">
+<script src="script.js"></script>`,
+							),
+						),
+					},
+					"http://host/style.css": {
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`<a href="notparsed">Not parsed</a>`)),
+					},
+					"http://host/image.png": {
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`image`)),
+					},
+				},
+				do: (*mockHTTPClient).defaultDo,
+			},
+			expected: map[string]int{
+				"http://host/start":        http.StatusOK,
+				"http://host/style.css":    http.StatusOK,
+				"http://host/image.png":    http.StatusOK,
+				"http://host/image-2x.png": http.StatusNotFound,
+				"http://host/script.js":    http.StatusNotFound,
+			},
+		},
+
+		{
+			name: "alternate pages",
+			cfg:  defaultConfig,
+			httpClient: &mockHTTPClient{
+				data: map[string]*http.Response{
+					"http://host/start": {
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`<link rel="alternate" hreflang="de" href="/de/">`)),
+					},
+					"http://host/de/": {
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`<a href="about">Über uns</a>`)),
+					},
+				},
+				do: (*mockHTTPClient).defaultDo,
+			},
+			expected: map[string]int{
+				"http://host/start":    http.StatusOK,
+				"http://host/de/":      http.StatusOK,
+				"http://host/de/about": http.StatusNotFound,
+			},
+		},
+
+		{
+			name: "relative links",
+			cfg:  defaultConfig,
+			httpClient: &mockHTTPClient{
+				data: map[string]*http.Response{
+					"http://host/start": {
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`<a href="docs/guide/">Guide</a>`)),
+					},
+					"http://host/docs/guide/": {
+						StatusCode: http.StatusOK,
+						Body: io.NopCloser(
+							strings.NewReader(
+								`<a href="nested">Nested</a>
+<a href="based/">Based</a>`,
+							),
+						),
+					},
+					"http://host/docs/guide/based/": {
+						StatusCode: http.StatusOK,
+						Body: io.NopCloser(
+							strings.NewReader(
+								`<html><head><base href="/other/"></head>
+<body><a href="nested">Nested</a></body></html>`,
+							),
+						),
+					},
+				},
+				do: (*mockHTTPClient).defaultDo,
+			},
+			expected: map[string]int{
+				"http://host/start":             http.StatusOK,
+				"http://host/docs/guide/":       http.StatusOK,
+				"http://host/docs/guide/nested": http.StatusNotFound,
+				"http://host/docs/guide/based/": http.StatusOK,
+				"http://host/other/nested":      http.StatusNotFound,
+			},
+		},
+
+		{
+			name: "fragments",
+			cfg:  defaultConfig,
+			httpClient: &mockHTTPClient{
+				data: map[string]*http.Response{
+					"http://host/start": {
+						StatusCode: http.StatusOK,
+						Body: io.NopCloser(
+							strings.NewReader(
+								`<h2 id="present">Present</h2>
+<a href="#present">Present</a>
+<a href="#missing">Missing</a>
+<a href="#top">Top</a>
+<a href="page#anchor">Page anchor</a>
+<a href="page#missing">Page missing</a>
+<a href="notfound#anchor">Not found</a>`,
+							),
+						),
+					},
+					"http://host/page": {
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`<a name="anchor">Anchor</a>`)),
+					},
+				},
+				do: (*mockHTTPClient).defaultDo,
+			},
+			expected: map[string]int{
+				"http://host/start":         http.StatusOK,
+				"http://host/page":          http.StatusOK,
+				"http://host/notfound":      http.StatusNotFound,
+				"http://host/start#missing": statusAnchorNotFound,
+				"http://host/page#missing":  statusAnchorNotFound,
+			},
+		},
+
+		{
+			name: "robots",
+			cfg:  defaultConfig,
+			httpClient: &mockHTTPClient{
+				data: map[string]*http.Response{
+					"http://host/robots.txt": {
+						StatusCode: http.StatusOK,
+						Body: io.NopCloser(
+							strings.NewReader("User-agent: *\nDisallow: /some/\n\nUser-agent: links\nDisallow: /link1\n"),
+						),
+					},
+					"http://host/start": {
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(defaultHTML)),
+					},
+					"http://host/link3": {
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`no links here`)),
+					},
+				},
+				do: (*mockHTTPClient).defaultDo,
+			},
+			expected: map[string]int{
+				"http://host/start":      http.StatusOK,
+				"http://host/link1":      statusRobotsDisallowed,
+				"http://host/some/link2": http.StatusNotFound,
+				"http://other.host":      statusExternalLink,
+				"http://host/error":      statusError,
+				"http://host/link3":      http.StatusOK,
+			},
+		},
+
+		{
+			name: "robots server error",
+			cfg:  defaultConfig,
+			before: func(t *testing.T) injectables {
+				return injectables{
+					errPrintFn: func(a ...any) (n int, err error) {
+						require.Equal(t, "http://host/robots.txt", a[0])
+						return 0, nil
+					},
+				}
+			},
+			httpClient: &mockHTTPClient{
+				data: map[string]*http.Response{
+					"http://host/robots.txt": {StatusCode: http.StatusServiceUnavailable},
+				},
+				do: (*mockHTTPClient).defaultDo,
+			},
+			expected: map[string]int{
+				"http://host/start": statusRobotsDisallowed,
+			},
+		},
+
+		{
+			name: "ignore robots",
+			cfg: &inspectorConfig{
+				Host:             "http://host",
+				LogExternalLinks: true,
+				RetryDelay:       10 * time.Millisecond,
+				RetryAttempts:    3,
+				IgnoreRobotsTxt:  true,
+			},
+			httpClient: &mockHTTPClient{
+				data: map[string]*http.Response{
+					"http://host/robots.txt": {
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader("User-agent: *\nDisallow: /\n")),
+					},
+					"http://host/start": {
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`no links here`)),
+					},
+				},
+				do: (*mockHTTPClient).defaultDo,
+			},
+			expected: map[string]int{
+				"http://host/start": http.StatusOK,
+			},
+		},
+
+		{
+			name: "max depth",
+			cfg: &inspectorConfig{
+				Host:          "http://host",
+				RetryDelay:    10 * time.Millisecond,
+				RetryAttempts: 3,
+				MaxDepth:      1,
+			},
+			httpClient: &mockHTTPClient{
+				data: map[string]*http.Response{
+					"http://host/start": {
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`<a href="level1">Level 1</a>`)),
+					},
+					"http://host/level1": {
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`<a href="level2">Level 2</a>`)),
+					},
+				},
+				do: (*mockHTTPClient).defaultDo,
+			},
+			expected: map[string]int{
+				"http://host/start":  http.StatusOK,
+				"http://host/level1": http.StatusOK,
+			},
+		},
+
+		{
+			name: "max pages",
+			cfg: &inspectorConfig{
+				Host:           "http://host",
+				RetryDelay:     10 * time.Millisecond,
+				RetryAttempts:  3,
+				MaxPages:       2,
+				ReportExcluded: true,
+			},
+			httpClient: &mockHTTPClient{
+				data: map[string]*http.Response{
+					"http://host/start": {
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`<img src="image.png"><a href="page1">Page 1</a>`)),
+					},
+					"http://host/image.png": {StatusCode: http.StatusOK},
+					"http://host/page1": {
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`<a href="page2">Page 2</a>`)),
+					},
+				},
+				do: (*mockHTTPClient).defaultDo,
+			},
+			expected: map[string]int{
+				"http://host/start":     http.StatusOK,
+				"http://host/image.png": http.StatusOK,
+				"http://host/page1":     http.StatusOK,
+				"http://host/page2":     statusOutOfScope,
+			},
+		},
+
+		{
+			name: "include and exclude",
+			cfg: &inspectorConfig{
+				Host:           "http://host",
+				RetryDelay:     10 * time.Millisecond,
+				RetryAttempts:  3,
+				Include:        []string{"/docs/**"},
+				Exclude:        []string{"/docs/api/**"},
+				ReportExcluded: true,
+			},
+			httpClient: &mockHTTPClient{
+				data: map[string]*http.Response{
+					"http://host/start": {
+						StatusCode: http.StatusOK,
+						Body: io.NopCloser(
+							strings.NewReader(
+								`<a href="/docs/guide">Guide</a><a href="/docs/api/v1">API</a><a href="/blog">Blog</a>`,
+							),
+						),
+					},
+					"http://host/docs/guide": {
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`no links here`)),
+					},
+				},
+				do: (*mockHTTPClient).defaultDo,
+			},
+			expected: map[string]int{
+				"http://host/start":       http.StatusOK,
+				"http://host/docs/guide":  http.StatusOK,
+				"http://host/docs/api/v1": statusOutOfScope,
+				"http://host/blog":        statusOutOfScope,
+			},
+		},
+
+		{
+			name: "internal hosts",
+			cfg: &inspectorConfig{
+				Host:             "http://host",
+				LogExternalLinks: true,
+				RetryDelay:       10 * time.Millisecond,
+				RetryAttempts:    3,
+				InternalHosts:    []string{"*.docs.host", "https://www.site"},
+			},
+			httpClient: &mockHTTPClient{
+				data: map[string]*http.Response{
+					"http://host/start": {
+						StatusCode: http.StatusOK,
+						Body: io.NopCloser(
+							strings.NewReader(
+								`<a href="http://eu.docs.host/guide">Guide</a>
+<a href="https://host/secure">Secure</a>
+<a href="http://www.site/blog">Blog</a>
+<a href="http://docs.host">Docs host</a>`,
+							),
+						),
+					},
+					"http://eu.docs.host/guide": {
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`<a href="/api">API</a><a href="http://host/">Home</a>`)),
+					},
+				},
+				do: (*mockHTTPClient).defaultDo,
+			},
+			expected: map[string]int{
+				"http://host/start":         http.StatusOK,
+				"http://eu.docs.host/guide": http.StatusOK,
+				"http://eu.docs.host/api":   http.StatusNotFound,
+				"http://host/":              http.StatusNotFound,
+				"https://host/secure":       http.StatusNotFound,
+				"http://www.site/blog":      http.StatusNotFound,
+				"http://docs.host":          statusExternalLink,
+			},
+		},
+
+		{
+			name:      "multiple start URLs",
+			cfg:       defaultConfig,
+			startURLs: []string{"start", "http://other/docs"},
+			httpClient: &mockHTTPClient{
+				data: map[string]*http.Response{
+					"http://host/start": {
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`<a href="http://other/docs">Docs</a>`)),
+					},
+					"http://other/docs": {
+						StatusCode: http.StatusOK,
+						Body: io.NopCloser(
+							strings.NewReader(`<a href="/guide">Guide</a><a href="http://third/page">Third</a>`),
+						),
+					},
+				},
+				do: (*mockHTTPClient).defaultDo,
+			},
+			expected: map[string]int{
+				"http://host/start":  http.StatusOK,
+				"http://other/docs":  http.StatusOK,
+				"http://other/guide": http.StatusNotFound,
+				"http://third/page":  statusExternalLink,
+			},
+		},
+
 		{
 			name: "invalid host",
 			cfg:  defaultConfig,
@@ -321,6 +771,99 @@ func TestInspector(t *testing.T) {
 			},
 		},
 
+		{
+			name: "certificate error",
+			cfg:  defaultConfig,
+			httpClient: &mockHTTPClient{
+				data: map[string]*http.Response{
+					"http://host/start": {
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`<a href="link1">Link1</a><a href="certerror">Cert error</a>`)),
+					},
+				},
+				do: (*mockHTTPClient).defaultDo,
+			},
+			expected: map[string]int{
+				"http://host/start":     http.StatusOK,
+				"http://host/link1":     http.StatusNotFound,
+				"http://host/certerror": statusCertificate,
+			},
+		},
+
+		{
+			name: "retry status codes",
+			cfg: &inspectorConfig{
+				Host:             "http://host",
+				RetryDelay:       10 * time.Millisecond,
+				RetryAttempts:    3,
+				RetryStatusCodes: []int{http.StatusTooManyRequests, http.StatusServiceUnavailable},
+			},
+			httpClient: func() httpClient {
+				var attempts sync.Map
+
+				return &mockHTTPClient{
+					do: func(_ *mockHTTPClient, req *http.Request) (*http.Response, error) {
+						n, _ := attempts.LoadOrStore(req.URL.Path, &atomic.Int32{})
+						attempt := n.(*atomic.Int32).Add(1)
+
+						switch req.URL.Path {
+						case "/start":
+							return &http.Response{
+								StatusCode: http.StatusOK,
+								Body:       io.NopCloser(strings.NewReader(`<a href="busy">Busy</a><a href="down">Down</a>`)),
+							}, nil
+
+						case "/busy":
+							if attempt == 1 {
+								return &http.Response{
+									StatusCode: http.StatusTooManyRequests,
+									Header:     http.Header{"Retry-After": []string{"0"}},
+									Body:       http.NoBody,
+								}, nil
+							}
+
+							return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+
+						case "/down":
+							return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}, nil
+						}
+
+						return &http.Response{StatusCode: http.StatusNotFound}, nil
+					},
+				}
+			}(),
+			expected: map[string]int{
+				"http://host/start": http.StatusOK,
+				"http://host/busy":  http.StatusOK,
+				"http://host/down":  http.StatusServiceUnavailable,
+			},
+		},
+
+		{
+			name: "retry transient errors",
+			cfg:  defaultConfig,
+			httpClient: func() httpClient {
+				var attempts atomic.Int32
+
+				return &mockHTTPClient{
+					do: func(_ *mockHTTPClient, req *http.Request) (*http.Response, error) {
+						if req.URL.Path != "/start" {
+							return &http.Response{StatusCode: http.StatusNotFound}, nil
+						}
+
+						if attempts.Add(1) < 3 {
+							return nil, &url.Error{Op: "Get", URL: req.URL.String(), Err: io.ErrUnexpectedEOF}
+						}
+
+						return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`no links`))}, nil
+					},
+				}
+			}(),
+			expected: map[string]int{
+				"http://host/start": http.StatusOK,
+			},
+		},
+
 		{
 			name: "request timeout",
 			cfg: &inspectorConfig{
@@ -407,7 +950,7 @@ func TestInspector(t *testing.T) {
 			expected: map[string]int{
 				"http://host/start": http.StatusOK,
 			},
-			expectedErr: errors.New("error parsing page content: connection reset by peer"),
+			expectedErr: errors.New("error parsing page content: http://host/start: connection reset by peer"),
 		},
 
 		{
@@ -432,7 +975,7 @@ func TestInspector(t *testing.T) {
 			expected: map[string]int{
 				"http://host/start": http.StatusOK,
 			},
-			expectedErr: errors.New("error parsing page content: parse html error"),
+			expectedErr: errors.New("error parsing page content: http://host/start: parse html error"),
 		},
 
 		{
@@ -488,7 +1031,7 @@ func TestInspector(t *testing.T) {
 
 			errChannel := make(chan error, 1)
 			if test.expectedErr != nil {
-				deps.printFn = func(a ...any) (n int, err error) {
+				deps.errPrintFn = func(a ...any) (n int, err error) {
 					errChannel <- a[0].(error)
 					return 0, nil
 				}
@@ -497,6 +1040,7 @@ func TestInspector(t *testing.T) {
 			i, err := newInspector(
 				test.cfg,
 				test.httpClient,
+				test.httpClient,
 				&sync.Map{},
 				toPrint,
 				deps,
@@ -548,7 +1092,12 @@ func TestInspector(t *testing.T) {
 				}
 			}()
 
-			i.inspect(context.Background(), "start", doneInspecting)
+			startURLs := test.startURLs
+			if startURLs == nil {
+				startURLs = []string{"start"}
+			}
+
+			i.inspect(context.Background(), startURLs, doneInspecting)
 
 			<-done
 			wg.Wait()
@@ -561,3 +1110,239 @@ func TestInspector(t *testing.T) {
 		})
 	}
 }
+
+func TestInspector_ExternalLinkRequestedOnce(t *testing.T) {
+	// more pages than a byte holds, so that occurrences counter overflow is detected.
+	const pages = 300
+
+	data := map[string]*http.Response{}
+	start := strings.Builder{}
+	for n := range pages {
+		start.WriteString(fmt.Sprintf(`<a href="page%d">Page</a>`, n))
+		data[fmt.Sprintf("http://host/page%d", n)] = &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`<a href="http://other.host/footer">Footer</a>`)),
+		}
+	}
+	data["http://host/start"] = &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(start.String()))}
+
+	httpClient := &mockHTTPClient{data: data, do: (*mockHTTPClient).defaultDo}
+
+	var requests atomic.Int32
+	externalHTTPClient := &mockHTTPClient{
+		do: func(_ *mockHTTPClient, _ *http.Request) (*http.Response, error) {
+			requests.Add(1)
+			time.Sleep(10 * time.Millisecond)
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		},
+	}
+
+	cfg := &inspectorConfig{Host: "http://host", CheckExternalLinks: true, RetryAttempts: 1}
+
+	visitedURLs := &sync.Map{}
+	toPrint := make(chan *link, 1024)
+	done := make(chan struct{}, 1)
+
+	i, err := newInspector(cfg, httpClient, externalHTTPClient, visitedURLs, toPrint, injectables{})
+	require.NoError(t, err)
+
+	i.inspect(context.Background(), []string{"start"}, done)
+	<-done
+
+	require.Equal(t, int32(1), requests.Load())
+
+	l, ok := visitedURLs.Load("http://other.host/footer")
+	require.True(t, ok)
+	require.Equal(t, http.StatusOK, l.(*link).code)
+	require.Len(t, l.(*link).Referrers, pages)
+	require.Equal(t, pages-1, l.(*link).Occurrences)
+}
+
+func TestInspector_Referrers(t *testing.T) {
+	httpClient := &mockHTTPClient{
+		data: map[string]*http.Response{
+			"http://host/start": {
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(
+					strings.NewReader(
+						`<p id="links">Links:</p><ul>
+<li><a href="#links">Links</a>
+<li><a href="link1">Link1</a>
+<li><a href="link1">Link1</a>
+<li><a href="link2"><span>Link</span>
+  <b>2</b></a>
+</ul>`,
+					),
+				),
+			},
+			"http://host/link1": {
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`<a href="link2">Other</a><a href="#top">Top</a>`)),
+			},
+		},
+		do: (*mockHTTPClient).defaultDo,
+	}
+
+	visitedURLs := &sync.Map{}
+	toPrint := make(chan *link, 1024)
+	done := make(chan struct{}, 1)
+
+	i, err := newInspector(defaultConfig, httpClient, httpClient, visitedURLs, toPrint, injectables{})
+	require.NoError(t, err)
+
+	i.inspect(context.Background(), []string{"start"}, done)
+	<-done
+
+	expected := map[string][]referrer{
+		"http://host/start": nil,
+		"http://host/link1": {{URL: "http://host/start", Text: "Link1", Element: "a"}},
+		"http://host/link2": {
+			{URL: "http://host/start", Text: "Link 2", Element: "a"},
+			{URL: "http://host/link1", Text: "Other", Element: "a"},
+		},
+	}
+
+	for u, refs := range expected {
+		l, ok := visitedURLs.Load(u)
+		require.True(t, ok, u)
+		require.ElementsMatch(t, refs, l.(*link).Referrers, u)
+	}
+
+	l, _ := visitedURLs.Load("http://host/link1")
+	require.Equal(t, 1, l.(*link).Occurrences)
+
+	// links to the page own fragments are not occurrences.
+	l, _ = visitedURLs.Load("http://host/start")
+	require.Equal(t, 0, l.(*link).Occurrences)
+}
+
+func TestInspector_Sitemap(t *testing.T) {
+	var gzipped bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	_, err := gw.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc>http://host/start</loc></url>
+	<url><loc>http://host/link1</loc></url>
+	<url><loc>http://host/orphan</loc></url>
+	<url><loc>http://other.host/page</loc></url>
+</urlset>`))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	httpClient := &mockHTTPClient{
+		data: map[string]*http.Response{
+			"http://host/robots.txt": {
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader("Sitemap: http://host/sitemap_index.xml\n")),
+			},
+			"http://host/sitemap_index.xml": {
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<sitemap><loc>http://host/sitemap1.xml.gz</loc></sitemap>
+</sitemapindex>`)),
+			},
+			"http://host/sitemap1.xml.gz": {
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(&gzipped),
+			},
+			"http://host/start": {
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`<a href="link1">Link1</a><a href="unlisted">Unlisted</a>`)),
+			},
+			"http://host/link1": {
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`no links here`)),
+			},
+			"http://host/orphan": {
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(strings.NewReader(`<link rel="canonical" href="http://host/orphan">
+<a href="#top">Top</a><a href="link1">Link1</a>`)),
+			},
+			"http://host/unlisted": {
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`no links here`)),
+			},
+		},
+		do: (*mockHTTPClient).defaultDo,
+	}
+
+	cfg := &inspectorConfig{
+		Host:          "http://host",
+		RetryDelay:    10 * time.Millisecond,
+		RetryAttempts: 3,
+		Sitemap:       sitemapAuto,
+	}
+
+	visitedURLs := &sync.Map{}
+	toPrint := make(chan *link, 1024)
+	done := make(chan struct{}, 1)
+
+	i, err := newInspector(cfg, httpClient, httpClient, visitedURLs, toPrint, injectables{})
+	require.NoError(t, err)
+
+	i.inspect(context.Background(), []string{"start"}, done)
+	<-done
+
+	expected := map[string]string{
+		"http://host/start":    "",
+		"http://host/link1":    "",
+		"http://host/orphan":   sitemapIssueOrphan,
+		"http://host/unlisted": sitemapIssueUnlisted,
+	}
+
+	actual := make(map[string]string)
+	visitedURLs.Range(func(key, value any) bool {
+		actual[key.(string)] = value.(*link).SitemapIssue
+		return true
+	})
+
+	require.Equal(t, expected, actual)
+
+	l, _ := visitedURLs.Load("http://host/start")
+	require.Equal(t, 0, l.(*link).Occurrences)
+}
+
+func TestInspector_SitemapError(t *testing.T) {
+	httpClient := &mockHTTPClient{
+		data: map[string]*http.Response{
+			"http://host/start": {
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`no links here`)),
+			},
+		},
+		do: (*mockHTTPClient).defaultDo,
+	}
+
+	cfg := &inspectorConfig{
+		Host:          "http://host",
+		RetryDelay:    10 * time.Millisecond,
+		RetryAttempts: 3,
+		Sitemap:       "/missing.xml",
+	}
+
+	var printed, errPrinted []any
+
+	deps := injectables{
+		printFn: func(a ...any) (n int, err error) {
+			printed = append(printed, a...)
+			return 0, nil
+		},
+		errPrintFn: func(a ...any) (n int, err error) {
+			errPrinted = append(errPrinted, a...)
+			return 0, nil
+		},
+	}
+
+	toPrint := make(chan *link, 1024)
+	done := make(chan struct{}, 1)
+
+	i, err := newInspector(cfg, httpClient, httpClient, &sync.Map{}, toPrint, deps)
+	require.NoError(t, err)
+
+	i.inspect(context.Background(), []string{"start"}, done)
+	<-done
+
+	require.Empty(t, printed)
+	require.Equal(t, []any{"error reading sitemap:", "http://host/missing.xml, status:", http.StatusNotFound}, errPrinted)
+}
@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// junitTestSuites is a JUnit XML report root element.
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite is a JUnit XML report test suite element.
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Skipped   int              `xml:"skipped,attr"`
+	TestCases []*junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase is a JUnit XML report test case element, representing a single link check.
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+// junitMessage is a JUnit XML report test case failure or skipped element.
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// newJUnitReport creates a JUnit report from the given results.
+// Broken links are reported as failures, links, which have not been requested,
+// and links with skipped status codes are reported as skipped.
+// Results grouped per site are reported in a test suite per site.
+func newJUnitReport(results []*link, suiteName string, skipStatusCodes []int) *junitTestSuites {
+	report := &junitTestSuites{Name: applicationName}
+	suites := make(map[string]*junitTestSuite)
+
+	for _, l := range results {
+		name := suiteName
+		if l.Site != "" {
+			name = l.Site
+		}
+
+		suite, ok := suites[name]
+		if !ok {
+			suite = &junitTestSuite{Name: name}
+			suites[name] = suite
+			report.Suites = append(report.Suites, suite)
+		}
+
+		tc := &junitTestCase{Name: l.URL, ClassName: name}
+
+		switch {
+		case isUnchecked(l.code) || containsCode(skipStatusCodes, l.code):
+			tc.Skipped = &junitMessage{Message: l.Status}
+			suite.Skipped++
+			report.Skipped++
+
+		case isBroken(l.code):
+			tc.Failure = &junitMessage{Message: l.Status, Type: getStatusLabel(l.code), Text: getFailureText(l)}
+			suite.Failures++
+			report.Failures++
+		}
+
+		suite.TestCases = append(suite.TestCases, tc)
+		suite.Tests++
+		report.Tests++
+	}
+
+	// report always contains at least one test suite.
+	if len(report.Suites) == 0 {
+		report.Suites = []*junitTestSuite{{Name: suiteName, TestCases: []*junitTestCase{}}}
+	}
+
+	return report
+}
+
+// getFailureText returns the broken link failure details.
+func getFailureText(l *link) string {
+	var b strings.Builder
+
+	b.WriteString("Status: " + l.Status + "\n")
+
+	if l.RedirectTarget != "" {
+		b.WriteString("Redirect target: " + l.RedirectTarget + "\n")
+	}
+
+	if l.err != nil {
+		b.WriteString("Error: " + l.err.Error() + "\n")
+	}
+
+	if len(l.Referrers) > 0 {
+		b.WriteString("Referrers:\n" + joinReferrers(l.Referrers) + "\n")
+	}
+
+	return b.String()
+}
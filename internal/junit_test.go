@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJUnitReport(t *testing.T) {
+	results := []*link{
+		{URL: "http://host/link1", Status: "200", code: http.StatusOK},
+		{
+			URL:       "http://host/link2",
+			Status:    "404",
+			code:      http.StatusNotFound,
+			Referrers: []referrer{{URL: "http://host/link1", Text: "Link 2", Element: "a"}},
+		},
+		{URL: "http://host/link3", Status: "ERR", code: statusError, err: errors.New("connection refused")},
+		{URL: "http://other.host", Status: "EXT", code: statusExternalLink},
+		{URL: "http://host/link4", Status: "403", code: http.StatusForbidden},
+	}
+
+	b, err := xml.MarshalIndent(newJUnitReport(results, "http://host", []int{http.StatusForbidden}), "", "  ")
+	require.NoError(t, err)
+
+	expected := `<testsuites name="links" tests="5" failures="2" skipped="2">
+  <testsuite name="http://host" tests="5" failures="2" skipped="2">
+    <testcase name="http://host/link1" classname="http://host"></testcase>
+    <testcase name="http://host/link2" classname="http://host">
+      <failure message="404" type="404">Status: 404&#xA;Referrers:&#xA;http://host/link1 (a: &#34;Link 2&#34;)&#xA;</failure>
+    </testcase>
+    <testcase name="http://host/link3" classname="http://host">
+      <failure message="ERR" type="ERR">Status: ERR&#xA;Error: connection refused&#xA;</failure>
+    </testcase>
+    <testcase name="http://other.host" classname="http://host">
+      <skipped message="EXT"></skipped>
+    </testcase>
+    <testcase name="http://host/link4" classname="http://host">
+      <skipped message="403"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>`
+
+	require.Equal(t, expected, string(b))
+}
+
+func TestJUnitReport_Sites(t *testing.T) {
+	results := []*link{
+		{URL: "http://host/link1", Status: "200", code: http.StatusOK, Site: "http://host"},
+		{URL: "http://other/link2", Status: "EXT", code: statusExternalLink, Site: "http://other"},
+	}
+
+	b, err := xml.MarshalIndent(newJUnitReport(results, "http://host", nil), "", "  ")
+	require.NoError(t, err)
+
+	expected := `<testsuites name="links" tests="2" failures="0" skipped="1">
+  <testsuite name="http://host" tests="1" failures="0" skipped="0">
+    <testcase name="http://host/link1" classname="http://host"></testcase>
+  </testsuite>
+  <testsuite name="http://other" tests="1" failures="0" skipped="1">
+    <testcase name="http://other/link2" classname="http://other">
+      <skipped message="EXT"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>`
+
+	require.Equal(t, expected, string(b))
+}
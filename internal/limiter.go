@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// tokenBucket limits the rate of requests to one per interval, allowing bursts of up to burst requests.
+// Nil bucket or bucket with zero interval does not limit requests.
+type tokenBucket struct {
+	interval time.Duration
+	burst    float64
+	tokens   float64
+	last     time.Time
+	now      func() time.Time
+	mu       sync.Mutex
+}
+
+func newTokenBucket(interval time.Duration, burst int, now func() time.Time) *tokenBucket {
+	burst = max(burst, 1)
+
+	return &tokenBucket{interval: interval, burst: float64(burst), tokens: float64(burst), now: now}
+}
+
+// wait takes a token from the bucket, blocking until it is available or the context is done.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b == nil || b.interval <= 0 {
+		return nil
+	}
+
+	delay := b.reserve()
+	if delay == 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+// reserve takes a token from the bucket, even if it is not available yet, and returns the delay until it is.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if !b.last.IsZero() {
+		b.tokens = math.Min(b.burst, b.tokens+float64(now.Sub(b.last))/float64(b.interval))
+	}
+	b.last = now
+
+	b.tokens--
+
+	if b.tokens < 0 {
+		return time.Duration(-b.tokens * float64(b.interval))
+	}
+
+	return 0
+}
+
+// hostLimiter limits the rate of requests to each host with a separate token bucket.
+type hostLimiter struct {
+	interval time.Duration
+	burst    int
+	buckets  map[string]*tokenBucket
+	now      func() time.Time
+	mu       sync.Mutex
+}
+
+// newHostLimiter creates a limiter allowing the given number of requests per second to each host.
+// Zero requests per second value does not limit requests. Buckets are refilled according to the given clock.
+func newHostLimiter(requestsPerSecond float64, now func() time.Time) *hostLimiter {
+	l := &hostLimiter{buckets: make(map[string]*tokenBucket), now: now}
+
+	if requestsPerSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+		l.burst = int(requestsPerSecond)
+	}
+
+	return l
+}
+
+// wait blocks until a request to the given host is allowed or the context is done.
+func (l *hostLimiter) wait(ctx context.Context, host string) error {
+	return l.getBucket(host).wait(ctx)
+}
+
+// setCrawlDelay spaces requests to the given host by the crawl delay, if it exceeds the requests rate limit.
+func (l *hostLimiter) setCrawlDelay(host string, delay time.Duration) {
+	if delay <= l.interval {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.buckets[host] = newTokenBucket(delay, 1, l.now)
+}
+
+func (l *hostLimiter) getBucket(host string) *tokenBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[host]
+	if !ok {
+		b = newTokenBucket(l.interval, l.burst, l.now)
+		l.buckets[host] = b
+	}
+
+	return b
+}
@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func TestTokenBucket(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		burst    int
+		advance  []time.Duration // clock advance before each request.
+		expected []time.Duration // delay of each request.
+	}{
+		{
+			name:     "no burst",
+			interval: 50 * time.Millisecond,
+			burst:    1,
+			advance:  []time.Duration{0, 0, 0},
+			expected: []time.Duration{0, 50 * time.Millisecond, 100 * time.Millisecond},
+		},
+
+		{
+			name:     "burst",
+			interval: 50 * time.Millisecond,
+			burst:    3,
+			advance:  []time.Duration{0, 0, 0, 0},
+			expected: []time.Duration{0, 0, 0, 50 * time.Millisecond},
+		},
+
+		{
+			name:     "refill",
+			interval: 50 * time.Millisecond,
+			burst:    2,
+			advance:  []time.Duration{0, 0, 0, 150 * time.Millisecond, 0, 0},
+			expected: []time.Duration{0, 0, 50 * time.Millisecond, 0, 0, 50 * time.Millisecond},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clock := &fakeClock{t: time.Unix(0, 0)}
+			b := newTokenBucket(test.interval, test.burst, clock.now)
+
+			for n, advance := range test.advance {
+				clock.t = clock.t.Add(advance)
+				require.Equal(t, test.expected[n], b.reserve(), n)
+			}
+		})
+	}
+}
+
+func TestTokenBucket_Wait(t *testing.T) {
+	var b *tokenBucket
+	require.NoError(t, b.wait(context.Background()))
+
+	b = newTokenBucket(0, 0, time.Now)
+	for range 10 {
+		require.NoError(t, b.wait(context.Background()))
+	}
+
+	b = newTokenBucket(20*time.Millisecond, 1, time.Now)
+
+	start := time.Now()
+	for range 2 {
+		require.NoError(t, b.wait(context.Background()))
+	}
+	require.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+}
+
+func TestTokenBucket_ContextDone(t *testing.T) {
+	b := newTokenBucket(time.Minute, 1, time.Now)
+	require.NoError(t, b.wait(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, b.wait(ctx), context.Canceled)
+}
+
+func TestHostLimiter(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	l := newHostLimiter(20, clock.now)
+
+	// requests to different hosts are limited separately.
+	for range 20 {
+		require.Zero(t, l.getBucket("host1").reserve())
+		require.Zero(t, l.getBucket("host2").reserve())
+	}
+
+	require.Equal(t, 50*time.Millisecond, l.getBucket("host1").reserve())
+
+	// crawl delay not exceeding the rate limit is ignored.
+	l.setCrawlDelay("host2", 10*time.Millisecond)
+	require.Equal(t, 50*time.Millisecond, l.getBucket("host2").interval)
+
+	l.setCrawlDelay("host2", 100*time.Millisecond)
+	b := l.getBucket("host2")
+	require.Equal(t, 100*time.Millisecond, b.interval)
+	require.InDelta(t, 1, b.burst, 0)
+
+	unlimited := newHostLimiter(0, clock.now)
+	unlimited.setCrawlDelay("host", 50*time.Millisecond)
+
+	for n, expected := range []time.Duration{0, 50 * time.Millisecond, 100 * time.Millisecond} {
+		require.Equal(t, expected, unlimited.getBucket("host").reserve(), n)
+	}
+}
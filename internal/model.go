@@ -3,7 +3,10 @@ package internal
 import (
 	"io"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 )
 
 type sortableURL = string
@@ -34,32 +37,133 @@ func (s sortableURLs) Swap(i, j int) {
 	s[i], s[j] = s[j], s[i]
 }
 
+// referrer describes a page element referencing a link.
+type referrer struct {
+	URL     string `json:"url"`
+	Text    string `json:"text,omitempty"`
+	Element string `json:"element"`
+}
+
+func (r referrer) String() string {
+	if r.Text == "" {
+		return r.URL + " (" + r.Element + ")"
+	}
+
+	return r.URL + " (" + r.Element + ": \"" + r.Text + "\")"
+}
+
+// redirect is a redirect hop.
+// Unfollowed redirect is the link response itself, returned with inspector.doNotFollowRedirects.
+type redirect struct {
+	Code       int    `json:"code"`
+	Location   string `json:"location"`
+	unfollowed bool
+}
+
 type link struct {
-	body        io.ReadCloser
-	URL         string
-	Status      string
-	code        int
-	Occurrences byte
+	body           io.ReadCloser
+	URL            string
+	Status         string
+	RedirectTarget string
+	Error          string
+	Referrers      []referrer
+	Redirects      []redirect
+	SitemapIssue   string
+	Site           string // output only if results are grouped per site.
+	site           string // origin of the start URL the link was discovered from.
+	err            error
+	code           int
+	Occurrences    int
+	depth          uint
+	external       bool
+	resource       bool
+	mu             sync.Mutex
+}
+
+// getRedirectTarget returns the URL the link has been redirected to or an empty string.
+func (l *link) getRedirectTarget() string {
+	if len(l.Redirects) == 0 {
+		return ""
+	}
+
+	return l.Redirects[len(l.Redirects)-1].Location
+}
+
+// getResponseURL returns the URL the link response has been received from,
+// which is the last followed redirect target or the link URL.
+func (l *link) getResponseURL() string {
+	if len(l.Redirects) == 0 || l.Redirects[len(l.Redirects)-1].unfollowed {
+		return l.URL
+	}
+
+	return l.getRedirectTarget()
+}
+
+// addOccurrence registers one more occurrence of the link.
+// Referrer is recorded only once per page element.
+// Links without referrer, like pages listed in sitemap, are not counted as occurrences.
+func (l *link) addOccurrence(ref *referrer) {
+	if ref == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.Occurrences++
+
+	if !slices.Contains(l.Referrers, *ref) {
+		l.Referrers = append(l.Referrers, *ref)
+	}
 }
 
 const (
-	statusOK           = 200
-	statusExternalLink = 991
-	statusError        = 992
+	statusOK               = 200
+	statusExternalLink     = 991
+	statusError            = 992
+	statusAnchorNotFound   = 993
+	statusRobotsDisallowed = 994
+	statusOutOfScope       = 995
+	statusCertificate      = 996
 )
 
+var statuses = map[int]string{
+	statusError:            "ERR",
+	statusExternalLink:     "EXT",
+	statusAnchorNotFound:   "ANCHOR",
+	statusRobotsDisallowed: "ROBOTS",
+	statusOutOfScope:       "SKIP",
+	statusCertificate:      "CERT",
+}
+
+// isUnchecked checks whether the status code represents a link, which has not been requested.
+func isUnchecked(code int) bool {
+	return code == statusExternalLink || code == statusRobotsDisallowed || code == statusOutOfScope
+}
+
+// getStatusLabel returns the status code representation.
+func getStatusLabel(code int) string {
+	if s, ok := statuses[code]; ok {
+		return s
+	}
+
+	return strconv.Itoa(code)
+}
+
 type outputFormat string
 
 func (o outputFormat) isFile() bool {
-	return o == outputFormatHTML || o == outputFormatCSV
+	return o == outputFormatHTML || o == outputFormatCSV || o == outputFormatJUnit
 }
 
 const (
 	outputFormatStdOut outputFormat = "stdout"
 	outputFormatHTML   outputFormat = "html"
 	outputFormatCSV    outputFormat = "csv"
+	outputFormatJUnit  outputFormat = "junit"
 	outputFormatYAML   outputFormat = "yaml"
 	outputFormatJSON   outputFormat = "json"
+	outputFormatNDJSON outputFormat = "ndjson"
 )
 
 type httpClient interface {
@@ -1,39 +1,88 @@
 package internal
 
 import (
+	"bytes"
 	"context"
 	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/ygrebnov/links/templates"
 )
 
-const fallbackToConsoleMsg = "error generating file, printing results to console"
+const (
+	fallbackToConsoleMsg = "error generating file, printing results to console"
+
+	// stdoutOutputPath is a printer.outputPath value for outputting file reports to stdout.
+	stdoutOutputPath = "-"
+
+	// reportTimestampFormat is a format of the timestamp in report file names.
+	reportTimestampFormat = "20060102T150405Z"
+)
 
 type printer interface {
 	run(ctx context.Context, toPrint <-chan *link, finalize <-chan struct{}, done chan<- struct{})
 }
 
 type defaultPrinter struct {
-	cfg  *printerConfig
-	deps injectables
-	data *sync.Map
-	wg   sync.WaitGroup
+	cfg          *printerConfig
+	inspectorCfg *inspectorConfig
+	deps         injectables
+	data         *sync.Map
+	sites        []string // results are grouped per site, if there are several ones.
+	wg           sync.WaitGroup
+}
+
+// linkResult is a link representation in JSON output formats.
+type linkResult struct {
+	URL         string     `json:"url"`
+	Code        int        `json:"code,omitempty"`
+	Status      string     `json:"status"`
+	Occurrences int        `json:"occurrences"`
+	Redirects   []redirect `json:"redirects,omitempty"`
+	Referrers   []referrer `json:"referrers,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+	Sitemap     string     `json:"sitemap,omitempty"`
+	Site        string     `json:"site,omitempty"`
+}
+
+// sitemapIssueResult is a sitemap issue representation in NDJSON output format.
+type sitemapIssueResult struct {
+	URL     string `json:"url"`
+	Sitemap string `json:"sitemap"`
+}
+
+// jsonReport is an inspection report in JSON output format.
+type jsonReport struct {
+	Results []*linkResult `json:"results"`
+	Summary *summary      `json:"summary"`
 }
 
-func newPrinter(cfg *printerConfig, deps injectables, data *sync.Map) printer {
+// newPrinter creates a new printer.
+// Inspector configuration is used for excluding links with skipped status codes and computing summary.
+func newPrinter(cfg *printerConfig, inspectorCfg *inspectorConfig, deps injectables, data *sync.Map) printer {
 	if cfg == nil {
 		cfg = &printerConfig{}
 	}
 
-	return &defaultPrinter{cfg: cfg, deps: deps, data: data}
+	if inspectorCfg == nil {
+		inspectorCfg = &inspectorConfig{}
+	}
+
+	return &defaultPrinter{cfg: cfg, inspectorCfg: inspectorCfg, deps: deps, data: data, sites: getSites(inspectorCfg)}
 }
 
 func (p *defaultPrinter) run(
@@ -50,9 +99,16 @@ func (p *defaultPrinter) run(
 				go p.printOne(l)
 
 			case <-finalize:
+				// print links sent right before finalizing.
+				for len(toPrint) > 0 {
+					p.wg.Add(1)
+					go p.printOne(<-toPrint)
+				}
+
 				p.wg.Wait() // wait for all p.printOne to finish.
 
 				p.printAll(ctx)
+				p.printSitemapIssues()
 
 				done <- struct{}{}
 			}
@@ -60,36 +116,65 @@ func (p *defaultPrinter) run(
 	}()
 }
 
-var statuses = map[int]string{
-	statusError:        "ERR",
-	statusExternalLink: "EXT",
+// getStatusChain returns the link status preceded by its redirect hops statuses.
+func (p *defaultPrinter) getStatusChain(l *link) string {
+	s := make([]string, 0, len(l.Redirects)+1)
+	for _, r := range l.Redirects {
+		// unfollowed redirect status is the link status.
+		if !r.unfollowed {
+			s = append(s, getStatusLabel(r.Code))
+		}
+	}
+
+	return strings.Join(append(s, getLinkStatusLabel(l)), " -> ")
 }
 
-func (p *defaultPrinter) getStatus(code int) string {
-	if s, ok := statuses[code]; ok {
-		return s
+// getLinkStatusLabel returns the link status representation followed by the request error reason, if any.
+func getLinkStatusLabel(l *link) string {
+	if reason := getErrorReason(l.err); reason != "" {
+		return getStatusLabel(l.code) + " (" + reason + ")"
 	}
 
-	return strconv.Itoa(code)
+	return getStatusLabel(l.code)
+}
+
+// getURLChain returns the link URL followed by its redirect target, if any.
+func getURLChain(l *link) string {
+	if target := l.getRedirectTarget(); target != "" {
+		return l.URL + " -> " + target
+	}
+
+	return l.URL
 }
 
 func (p *defaultPrinter) printOne(l *link) {
 	defer p.wg.Done()
 
 	if p.cfg.SortOutput ||
+		p.isGrouped() ||
 		p.cfg.DisplayOccurrences ||
+		p.cfg.DisplayReferrers ||
 		p.cfg.OutputFormat.isFile() ||
+		p.cfg.OutputFormat == outputFormatJSON ||
 		(l.code == statusOK && p.cfg.SkipOK) {
 		return
 	}
 
-	_, _ = p.deps.getPrintFn()(p.getStatus(l.code), "-", l.URL)
+	if p.cfg.OutputFormat == outputFormatNDJSON {
+		p.printJSON(newLinkResult(l), false)
+		return
+	}
+
+	_, _ = p.deps.getPrintFn()(p.getStatusChain(l), "-", getURLChain(l))
 }
 
 func (p *defaultPrinter) printAll(ctx context.Context) {
 	if !p.cfg.SortOutput &&
+		!p.isGrouped() &&
 		!p.cfg.DisplayOccurrences &&
-		!p.cfg.OutputFormat.isFile() {
+		!p.cfg.DisplayReferrers &&
+		!p.cfg.OutputFormat.isFile() &&
+		p.cfg.OutputFormat != outputFormatJSON {
 		return
 	}
 
@@ -105,11 +190,30 @@ func (p *defaultPrinter) printAll(ctx context.Context) {
 		sort.Sort(keys)
 	}
 
+	if p.isGrouped() {
+		p.groupBySite(keys)
+	}
+
 	results := p.printResults(keys)
 
+	if p.cfg.OutputFormat == outputFormatJSON {
+		report := &jsonReport{
+			Results: make([]*linkResult, 0, len(results)),
+			Summary: newSummary(p.inspectorCfg, p.data),
+		}
+
+		for _, l := range results {
+			report.Results = append(report.Results, newLinkResult(l))
+		}
+
+		p.printJSON(report, true)
+
+		return
+	}
+
 	defer func() {
 		if ePanic := recover(); ePanic != nil {
-			fmt.Println(fallbackToConsoleMsg, ePanic)
+			_, _ = p.deps.getErrPrintFn()(fallbackToConsoleMsg, ePanic)
 
 			p.cfg.OutputFormat = outputFormatStdOut
 			p.printResults(keys)
@@ -117,40 +221,167 @@ func (p *defaultPrinter) printAll(ctx context.Context) {
 	}()
 
 	if err := p.generateFile(ctx, results); err != nil {
-		fmt.Println(fallbackToConsoleMsg, err)
+		_, _ = p.deps.getErrPrintFn()(fallbackToConsoleMsg, err)
 
 		p.cfg.OutputFormat = outputFormatStdOut
 		p.printResults(keys)
 	}
 }
 
+// isGrouped checks whether results are grouped per site.
+func (p *defaultPrinter) isGrouped() bool {
+	return len(p.sites) > 1
+}
+
+// groupBySite orders the links by their sites, keeping the links order within each site.
+// Links, which do not belong to any site, are put last.
+func (p *defaultPrinter) groupBySite(keys sortableURLs) {
+	index := func(k sortableURL) int {
+		l, _ := p.data.Load(k)
+		if i := slices.Index(p.sites, l.(*link).site); i >= 0 {
+			return i
+		}
+
+		return len(p.sites)
+	}
+
+	sort.SliceStable(keys, func(a, b int) bool {
+		return index(keys[a]) < index(keys[b])
+	})
+}
+
 func (p *defaultPrinter) printResults(keys sortableURLs) []*link {
 	results := make([]*link, 0, len(keys))
 
+	site := ""
 	for _, k := range keys {
 		l, _ := p.data.Load(k)
 		lTyped := l.(*link)
 
+		if p.isGrouped() {
+			lTyped.Site = lTyped.site
+		}
+
+		// site header precedes its links in console output.
+		if p.isGrouped() && lTyped.Site != site && !p.cfg.OutputFormat.isFile() &&
+			p.cfg.OutputFormat != outputFormatJSON && p.cfg.OutputFormat != outputFormatNDJSON {
+			site = lTyped.Site
+			_, _ = p.deps.getPrintFn()("site:", site)
+		}
+
 		switch {
 		case p.cfg.SkipOK && lTyped.code == statusOK:
 			continue
 
+		case containsCode(p.inspectorCfg.SkipStatusCodes, lTyped.code) && p.cfg.OutputFormat != outputFormatJUnit:
+			continue // links with skipped status codes are reported as skipped in JUnit format.
+
+		case p.cfg.OutputFormat == outputFormatJSON:
+			results = append(results, lTyped)
+
+		case p.cfg.OutputFormat == outputFormatNDJSON:
+			p.printJSON(newLinkResult(lTyped), false)
+
 		case p.cfg.OutputFormat.isFile():
 			lTyped.Occurrences++
-			lTyped.Status = p.getStatus(lTyped.code)
+			lTyped.Status = p.getStatusChain(lTyped)
+			lTyped.RedirectTarget = lTyped.getRedirectTarget()
+			if lTyped.err != nil {
+				lTyped.Error = lTyped.err.Error()
+			}
 			results = append(results, lTyped)
 
 		case p.cfg.DisplayOccurrences:
-			_, _ = p.deps.getPrintFn()(p.getStatus(lTyped.code), "-", lTyped.Occurrences+1, "-", k)
+			_, _ = p.deps.getPrintFn()(p.getStatusChain(lTyped), "-", lTyped.Occurrences+1, "-", getURLChain(lTyped))
+			p.printReferrers(lTyped)
 
 		default:
-			_, _ = p.deps.getPrintFn()(p.getStatus(lTyped.code), "-", k)
+			_, _ = p.deps.getPrintFn()(p.getStatusChain(lTyped), "-", getURLChain(lTyped))
+			p.printReferrers(lTyped)
 		}
 	}
 
 	return results
 }
 
+// printJSON outputs the given value in JSON format.
+func (p *defaultPrinter) printJSON(v any, indent bool) {
+	var (
+		b   []byte
+		err error
+	)
+
+	if indent {
+		b, err = json.MarshalIndent(v, "", "\t")
+	} else {
+		b, err = json.Marshal(v)
+	}
+
+	if err != nil {
+		_, _ = p.deps.getErrPrintFn()(fmt.Errorf("cannot marshal results into json: %w", err))
+		return
+	}
+
+	_, _ = p.deps.getPrintFn()(string(b))
+}
+
+// newLinkResult creates the given link representation in JSON output formats.
+func newLinkResult(l *link) *linkResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r := &linkResult{
+		URL:         l.URL,
+		Status:      getStatusLabel(l.code),
+		Occurrences: l.Occurrences + 1,
+		Redirects:   l.Redirects,
+		Referrers:   slices.Clone(l.Referrers),
+		Sitemap:     l.SitemapIssue,
+		Site:        l.Site,
+	}
+
+	if _, synthetic := statuses[l.code]; !synthetic {
+		r.Code = l.code
+	}
+
+	if l.err != nil {
+		r.Error = l.err.Error()
+		r.Reason = getErrorReason(l.err)
+	}
+
+	return r
+}
+
+// printSitemapIssues outputs orphan and unlisted pages once inspection is finished.
+// In JSON output format, sitemap issues are included into the report.
+func (p *defaultPrinter) printSitemapIssues() {
+	if p.inspectorCfg.Sitemap == "" ||
+		p.cfg.OutputFormat == outputFormatJSON ||
+		(p.cfg.OutputFormat.isFile() && p.cfg.OutputPath == stdoutOutputPath) {
+		return
+	}
+
+	for _, l := range getSitemapIssues(p.data) {
+		if p.cfg.OutputFormat == outputFormatNDJSON {
+			p.printJSON(&sitemapIssueResult{URL: l.URL, Sitemap: l.SitemapIssue}, false)
+			continue
+		}
+
+		_, _ = p.deps.getPrintFn()(l.SitemapIssue, "page -", l.URL)
+	}
+}
+
+// printReferrers outputs pages referencing the given link, one per line.
+func (p *defaultPrinter) printReferrers(l *link) {
+	if !p.cfg.DisplayReferrers {
+		return
+	}
+
+	for _, r := range l.Referrers {
+		_, _ = p.deps.getPrintFn()("\treferrer:", r.String())
+	}
+}
+
 func (p *defaultPrinter) generateFile(ctx context.Context, results []*link) error {
 	if !p.cfg.OutputFormat.isFile() {
 		return nil
@@ -166,12 +397,18 @@ func (p *defaultPrinter) generateFile(ctx context.Context, results []*link) erro
 		path, err = p.generateHTMLFile(results)
 	case outputFormatCSV:
 		path, err = p.generateCSVFile(results)
+	case outputFormatJUnit:
+		path, err = p.generateJUnitFile(results)
 	}
 
 	if err != nil {
 		return err
 	}
 
+	if path == stdoutOutputPath {
+		return nil
+	}
+
 	fmt.Println("generated report:", path)
 
 	if p.cfg.DoNotOpenFileReport {
@@ -191,16 +428,68 @@ func (p *defaultPrinter) generateFile(ctx context.Context, results []*link) erro
 	return cmd.Run()
 }
 
-// createFile creates a report file with the given name and returns its path and file handle.
-// The file is created in the temporary directory.
-func (p *defaultPrinter) createFile(name string) (path string, file *os.File, err error) {
-	path = filepath.Join(p.deps.getTempDir()(), name)
+// createFile creates a report file and returns its path and file handle.
+// The file is created at printer.outputPath or, if it is not set, in the temporary directory.
+// Given name is used, if printer.outputPath is a directory.
+// In case printer.outputPath is "-", report is output to stdout on file handle closing.
+func (p *defaultPrinter) createFile(name string) (path string, file io.WriteCloser, err error) {
+	if p.cfg.OutputPath == stdoutOutputPath {
+		return stdoutOutputPath, &printFnWriter{printFn: p.deps.getPrintFn()}, nil
+	}
+
+	if path, err = p.getReportPath(name); err != nil {
+		return "", nil, err
+	}
 
 	file, err = os.Create(path)
 
 	return
 }
 
+// getReportPath returns the report file path with {host} and {timestamp} placeholders replaced.
+// Parent directories are created, if needed.
+func (p *defaultPrinter) getReportPath(name string) (string, error) {
+	if p.cfg.OutputPath == "" {
+		return filepath.Join(p.deps.getTempDir()(), name), nil
+	}
+
+	host := p.inspectorCfg.Host
+	if u, err := url.Parse(host); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	path := strings.NewReplacer(
+		"{host}", strings.ReplaceAll(host, ":", "_"),
+		"{timestamp}", p.deps.getNow()().UTC().Format(reportTimestampFormat),
+	).Replace(p.cfg.OutputPath)
+
+	isDir := strings.HasSuffix(path, "/") || strings.HasSuffix(path, string(filepath.Separator))
+	if fi, err := p.deps.getStat()(path); err == nil && fi.IsDir() {
+		isDir = true
+	}
+
+	if isDir {
+		path = filepath.Join(path, name)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// printFnWriter accumulates written data and outputs it with printFn on closing.
+type printFnWriter struct {
+	bytes.Buffer
+	printFn func(a ...any) (n int, err error)
+}
+
+func (w *printFnWriter) Close() error {
+	_, err := w.printFn(strings.TrimSuffix(w.String(), "\n"))
+	return err
+}
+
 // generateHTMLFile generates an HTML file with the results.
 func (p *defaultPrinter) generateHTMLFile(results []*link) (string, error) {
 	t, err := p.deps.getTemplateParseFiles()(templates.GetLinksTemplate(), "links.html")
@@ -237,20 +526,30 @@ func (p *defaultPrinter) generateCSVFile(results []*link) (string, error) {
 	}()
 
 	w := csv.NewWriter(file)
-	err = w.Write([]string{"Status", "Occurrences", "URL"})
-	if err != nil {
+
+	header := []string{"Status", "Occurrences", "URL", "Redirect target", "Error", "Referrers"}
+	if p.isGrouped() {
+		header = slices.Insert(header, 0, "Site")
+	}
+
+	if err = w.Write(header); err != nil {
 		return "", err
 	}
 
 	for _, l := range results {
-		err = w.Write(
-			[]string{
-				l.Status,
-				strconv.Itoa(int(l.Occurrences + 1)),
-				l.URL,
-			},
-		)
-		if err != nil {
+		record := []string{
+			l.Status,
+			strconv.Itoa(l.Occurrences),
+			l.URL,
+			l.RedirectTarget,
+			l.Error,
+			joinReferrers(l.Referrers),
+		}
+		if p.isGrouped() {
+			record = slices.Insert(record, 0, l.Site)
+		}
+
+		if err = w.Write(record); err != nil {
 			return "", err
 		}
 	}
@@ -259,3 +558,38 @@ func (p *defaultPrinter) generateCSVFile(results []*link) (string, error) {
 
 	return path, nil
 }
+
+// generateJUnitFile generates a JUnit XML file with the results.
+func (p *defaultPrinter) generateJUnitFile(results []*link) (string, error) {
+	path, file, err := p.createFile("links.xml")
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	if _, err = io.WriteString(file, xml.Header); err != nil {
+		return "", err
+	}
+
+	e := xml.NewEncoder(file)
+	e.Indent("", "  ")
+
+	if err = e.Encode(newJUnitReport(results, p.inspectorCfg.Host, p.inspectorCfg.SkipStatusCodes)); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// joinReferrers returns referrers representation suitable for a single report cell.
+func joinReferrers(refs []referrer) string {
+	s := make([]string, 0, len(refs))
+	for _, r := range refs {
+		s = append(s, r.String())
+	}
+
+	return strings.Join(s, "\n")
+}
@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"syscall"
 	"testing"
 	"time"
 
@@ -37,16 +39,20 @@ func (t *mockTemplate) Execute(file io.Writer, data interface{}) error {
 }
 
 func TestPrinter(t *testing.T) {
+	outputDir := t.TempDir()
+
 	tests := []struct {
-		name       string
-		before     func(t *testing.T) injectables
-		tempDir    string
-		cfg        *printerConfig
-		data       []*link
-		expected   []string
-		checkOrder bool
-		checkFile  bool
-		fileName   string
+		name         string
+		before       func(t *testing.T) injectables
+		tempDir      string
+		cfg          *printerConfig
+		inspectorCfg *inspectorConfig
+		data         []*link
+		expected     []string
+		expectedErrs []string
+		checkOrder   bool
+		checkFile    bool
+		fileName     string
 	}{
 		{
 			name: "nominal",
@@ -86,6 +92,59 @@ func TestPrinter(t *testing.T) {
 			expected: []string{"200 - 25 - link1", "404 - 2 - link2", "ERR - 1 - link3", "EXT - 1 - link4"},
 		},
 
+		{
+			name: "display referrers",
+			cfg:  &printerConfig{DisplayReferrers: true, SortOutput: true},
+			data: []*link{
+				{
+					URL:       "link2",
+					code:      http.StatusNotFound,
+					Referrers: []referrer{{URL: "link1", Text: "Link 2", Element: "a"}, {URL: "link3", Element: "a"}},
+				},
+				{URL: "link1", code: http.StatusOK},
+			},
+			expected: []string{
+				"200 - link1",
+				"404 - link2",
+				"\treferrer: link1 (a: \"Link 2\")",
+				"\treferrer: link3 (a)",
+			},
+			checkOrder: true,
+		},
+
+		{
+			name: "redirects",
+			cfg:  nil,
+			data: []*link{
+				{
+					URL:  "link1",
+					code: http.StatusOK,
+					Redirects: []redirect{
+						{Code: http.StatusMovedPermanently, Location: "link2"},
+						{Code: http.StatusFound, Location: "link3"},
+					},
+				},
+				{URL: "link4", code: http.StatusNotFound},
+				{
+					URL:       "link5",
+					code:      http.StatusMovedPermanently,
+					Redirects: []redirect{{Code: http.StatusMovedPermanently, Location: "link6", unfollowed: true}},
+				},
+			},
+			expected: []string{"301 -> 302 -> 200 - link1 -> link3", "404 - link4", "301 - link5 -> link6"},
+		},
+
+		{
+			name: "error reasons",
+			cfg:  nil,
+			data: []*link{
+				{URL: "link1", code: statusError, err: &net.DNSError{Err: "no such host", Name: "link1"}},
+				{URL: "link2", code: statusError, err: syscall.ECONNREFUSED},
+				{URL: "link3", code: statusError},
+			},
+			expected: []string{"ERR (dns) - link1", "ERR (connection refused) - link2", "ERR - link3"},
+		},
+
 		{
 			name: "skip ok",
 			cfg:  &printerConfig{SkipOK: true},
@@ -111,6 +170,155 @@ func TestPrinter(t *testing.T) {
 			checkOrder: true,
 		},
 
+		{
+			name:         "skip status codes, sort output",
+			cfg:          &printerConfig{SortOutput: true},
+			inspectorCfg: &inspectorConfig{SkipStatusCodes: []int{http.StatusNotFound}},
+			data: []*link{
+				{URL: "link2", code: http.StatusNotFound},
+				{URL: "link1", code: http.StatusOK},
+				{URL: "link3", code: statusError},
+			},
+			expected:   []string{"200 - link1", "ERR - link3"},
+			checkOrder: true,
+		},
+
+		{
+			name: "grouped per site",
+			cfg:  &printerConfig{SortOutput: true},
+			inspectorCfg: &inspectorConfig{
+				Host:      "http://host",
+				StartURLs: []string{"http://other/docs", "/start"},
+			},
+			data: []*link{
+				{URL: "http://host/start", code: http.StatusOK, site: "http://host"},
+				{URL: "http://other/guide", code: http.StatusNotFound, site: "http://other"},
+				{URL: "http://other/docs", code: http.StatusOK, site: "http://other"},
+			},
+			expected: []string{
+				"site: http://other",
+				"200 - http://other/docs",
+				"404 - http://other/guide",
+				"site: http://host",
+				"200 - http://host/start",
+			},
+			checkOrder: true,
+		},
+
+		{
+			name: "grouped per site, csv output to stdout",
+			cfg:  &printerConfig{OutputFormat: outputFormatCSV, OutputPath: "-", SortOutput: true},
+			inspectorCfg: &inspectorConfig{
+				Host:      "http://host",
+				StartURLs: []string{"/start", "http://other/docs"},
+			},
+			data: []*link{
+				{URL: "http://other/docs", code: http.StatusOK, site: "http://other"},
+				{URL: "http://host/start", code: http.StatusOK, site: "http://host"},
+			},
+			expected: []string{
+				"Site,Status,Occurrences,URL,Redirect target,Error,Referrers\n" +
+					"http://host,200,1,http://host/start,,,\nhttp://other,200,1,http://other/docs,,,",
+			},
+		},
+
+		{
+			name: "json output",
+			cfg:  &printerConfig{OutputFormat: outputFormatJSON, SortOutput: true},
+			data: []*link{
+				{
+					URL:       "link2",
+					code:      http.StatusNotFound,
+					Referrers: []referrer{{URL: "link1", Text: "Link 2", Element: "a"}},
+				},
+				{
+					URL:       "link1",
+					code:      http.StatusOK,
+					Redirects: []redirect{{Code: http.StatusMovedPermanently, Location: "link4"}},
+				},
+				{URL: "link3", code: statusError, err: syscall.ECONNREFUSED},
+			},
+			expected: []string{`{
+	"results": [
+		{
+			"url": "link1",
+			"code": 200,
+			"status": "200",
+			"occurrences": 1,
+			"redirects": [
+				{
+					"code": 301,
+					"location": "link4"
+				}
+			]
+		},
+		{
+			"url": "link2",
+			"code": 404,
+			"status": "404",
+			"occurrences": 1,
+			"referrers": [
+				{
+					"url": "link1",
+					"text": "Link 2",
+					"element": "a"
+				}
+			]
+		},
+		{
+			"url": "link3",
+			"status": "ERR",
+			"occurrences": 1,
+			"error": "connection refused",
+			"reason": "connection refused"
+		}
+	],
+	"summary": {
+		"checked": 3,
+		"broken": 2,
+		"failed": 0,
+		"statuses": {
+			"200": 1,
+			"404": 1,
+			"ERR": 1
+		}
+	}
+}`},
+			checkOrder: true,
+		},
+
+		{
+			name: "ndjson output",
+			cfg:  &printerConfig{OutputFormat: outputFormatNDJSON},
+			data: []*link{
+				{URL: "link2", code: http.StatusNotFound, Occurrences: 2},
+				{URL: "link1", code: http.StatusOK},
+				{URL: "link3", code: statusError, err: syscall.ECONNREFUSED},
+			},
+			expected: []string{
+				`{"url":"link1","code":200,"status":"200","occurrences":1}`,
+				`{"url":"link2","code":404,"status":"404","occurrences":3}`,
+				`{"url":"link3","status":"ERR","occurrences":1,"error":"connection refused","reason":"connection refused"}`,
+			},
+		},
+
+		{
+			name:         "sitemap issues",
+			inspectorCfg: &inspectorConfig{Sitemap: sitemapAuto},
+			data: []*link{
+				{URL: "link1", code: http.StatusOK},
+				{URL: "link2", code: http.StatusOK, SitemapIssue: sitemapIssueOrphan},
+				{URL: "link3", code: http.StatusOK, SitemapIssue: sitemapIssueUnlisted},
+			},
+			expected: []string{
+				"200 - link1",
+				"200 - link2",
+				"200 - link3",
+				"orphan page - link2",
+				"unlisted page - link3",
+			},
+		},
+
 		{
 			name:    "html output",
 			tempDir: t.TempDir(),
@@ -141,6 +349,66 @@ func TestPrinter(t *testing.T) {
 			fileName:  "links.csv",
 		},
 
+		{
+			name:    "junit output",
+			tempDir: t.TempDir(),
+			cfg:     &printerConfig{OutputFormat: outputFormatJUnit, DoNotOpenFileReport: true},
+			data: []*link{
+				{URL: "link2", code: http.StatusNotFound},
+				{URL: "link4", code: statusExternalLink},
+				{URL: "link1", code: http.StatusOK},
+				{URL: "link3", code: statusError},
+			},
+			checkFile: true,
+			fileName:  "links.xml",
+		},
+
+		{
+			name: "output path with placeholders",
+			before: func(*testing.T) injectables {
+				return injectables{
+					now: func() time.Time {
+						return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+					},
+				}
+			},
+			tempDir: outputDir,
+			cfg: &printerConfig{
+				OutputFormat:        outputFormatCSV,
+				OutputPath:          filepath.Join(outputDir, "{host}", "links-{timestamp}.csv"),
+				DoNotOpenFileReport: true,
+			},
+			inspectorCfg: &inspectorConfig{Host: "http://example.com:8080"},
+			data:         []*link{{URL: "link1", code: http.StatusOK}},
+			checkFile:    true,
+			fileName:     filepath.Join("example.com_8080", "links-20260102T030405Z.csv"),
+		},
+
+		{
+			name:    "output path directory",
+			tempDir: filepath.Join(outputDir, "reports"),
+			cfg: &printerConfig{
+				OutputFormat:        outputFormatHTML,
+				OutputPath:          filepath.Join(outputDir, "reports") + string(filepath.Separator),
+				DoNotOpenFileReport: true,
+			},
+			data:      []*link{{URL: "link1", code: http.StatusOK}},
+			checkFile: true,
+			fileName:  "links.html",
+		},
+
+		{
+			name: "output file report to stdout",
+			cfg:  &printerConfig{OutputFormat: outputFormatCSV, OutputPath: "-", SortOutput: true},
+			data: []*link{
+				{URL: "link2", code: http.StatusNotFound},
+				{URL: "link1", code: http.StatusOK},
+			},
+			expected: []string{
+				"Status,Occurrences,URL,Redirect target,Error,Referrers\n200,1,link1,,,\n404,1,link2,,,",
+			},
+		},
+
 		{
 			name: "error parsing template",
 			before: func(*testing.T) injectables {
@@ -157,7 +425,8 @@ func TestPrinter(t *testing.T) {
 				{URL: "link1", code: http.StatusOK},
 				{URL: "link3", code: statusError},
 			},
-			expected: []string{"200 - link1", "404 - link2", "ERR - link3", "EXT - link4"},
+			expected:     []string{"200 - link1", "404 - link2", "ERR - link3", "EXT - link4"},
+			expectedErrs: []string{fallbackToConsoleMsg + " error parsing template"},
 		},
 
 		{
@@ -170,7 +439,8 @@ func TestPrinter(t *testing.T) {
 				{URL: "link1", code: http.StatusOK},
 				{URL: "link3", code: statusError},
 			},
-			expected: []string{"200 - link1", "404 - link2", "ERR - link3", "EXT - link4"},
+			expected:     []string{"200 - link1", "404 - link2", "ERR - link3", "EXT - link4"},
+			expectedErrs: []string{fallbackToConsoleMsg + " open -:/links.html: no such file or directory"},
 		},
 
 		{
@@ -194,7 +464,8 @@ func TestPrinter(t *testing.T) {
 				{URL: "link1", code: http.StatusOK},
 				{URL: "link3", code: statusError},
 			},
-			expected: []string{"200 - link1", "404 - link2", "ERR - link3", "EXT - link4"},
+			expected:     []string{"200 - link1", "404 - link2", "ERR - link3", "EXT - link4"},
+			expectedErrs: []string{fallbackToConsoleMsg + " error executing template"},
 		},
 
 		{
@@ -218,7 +489,8 @@ func TestPrinter(t *testing.T) {
 				{URL: "link1", code: http.StatusOK},
 				{URL: "link3", code: statusError},
 			},
-			expected: []string{"200 - link1", "404 - link2", "ERR - link3", "EXT - link4"},
+			expected:     []string{"200 - link1", "404 - link2", "ERR - link3", "EXT - link4"},
+			expectedErrs: []string{fallbackToConsoleMsg + " panic on executing template"},
 		},
 	}
 
@@ -239,11 +511,17 @@ func TestPrinter(t *testing.T) {
 
 			r := &res{}
 			deps.printFn = func(a ...any) (n int, err error) {
-				r.add(strings.Trim(fmt.Sprint(a), "[]"))
+				r.add(strings.TrimSuffix(fmt.Sprintln(a...), "\n"))
+				return 0, nil
+			}
+
+			errs := &res{}
+			deps.errPrintFn = func(a ...any) (n int, err error) {
+				errs.add(strings.TrimSuffix(fmt.Sprintln(a...), "\n"))
 				return 0, nil
 			}
 			data := &sync.Map{}
-			p := newPrinter(test.cfg, deps, data)
+			p := newPrinter(test.cfg, test.inspectorCfg, deps, data)
 
 			doneInspecting := make(chan struct{}, 1)
 			donePrinting := make(chan struct{}, 1)
@@ -286,6 +564,8 @@ func TestPrinter(t *testing.T) {
 			default:
 				require.ElementsMatch(t, test.expected, r.d)
 			}
+
+			require.Equal(t, test.expectedErrs, errs.d)
 		})
 	}
 }
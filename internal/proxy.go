@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// proxyDirect is a proxy URL value standing for connecting without proxy.
+const proxyDirect = "direct"
+
+// proxySchemes are supported proxy URL schemes.
+var proxySchemes = []string{"http", "https", "socks5", "socks5h"}
+
+// newProxyFunc creates a transport proxy function according to the given configuration.
+// Proxy is taken from the environment variables, if the configuration is not set.
+// Nil function is returned for direct connections.
+func newProxyFunc(cfg *proxyConfig) (func(*http.Request) (*url.URL, error), error) {
+	switch {
+	case cfg == nil:
+		return http.ProxyFromEnvironment, nil
+
+	case cfg.URL == proxyDirect:
+		return nil, nil
+	}
+
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	username, err := resolveSecret(cfg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := resolveSecret(cfg.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	if username != "" || password != "" {
+		u.User = url.UserPassword(username, password)
+	}
+
+	// requests to localhost are not proxied, like with the proxy environment variables.
+	proxyFunc := (&httpproxy.Config{
+		HTTPProxy:  u.String(),
+		HTTPSProxy: u.String(),
+		NoProxy:    strings.Join(cfg.NoProxy, ","),
+	}).ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}, nil
+}
@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+)
+
+// Request error reasons.
+const (
+	reasonDNS              = "dns"
+	reasonRefused          = "connection refused"
+	reasonReset            = "connection reset"
+	reasonTLS              = "tls"
+	reasonExpired          = "certificate expired"
+	reasonHostnameMismatch = "hostname mismatch"
+	reasonUnknownAuthority = "unknown authority"
+	reasonInvalidCert      = "invalid certificate"
+	reasonTimeout          = "timeout"
+	reasonInvalidURL       = "invalid url"
+	reasonTooManyRedirects = "too many redirects"
+	reasonOther            = "other"
+)
+
+// getErrorReason classifies the request error.
+// Empty string is returned for nil error.
+//
+//nolint:gocyclo // flat list of checks.
+func getErrorReason(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var (
+		dnsErr *net.DNSError
+		urlErr *url.Error
+		netErr net.Error
+	)
+
+	switch {
+	case errors.Is(err, ErrTooManyRedirects):
+		return reasonTooManyRedirects
+
+	case errors.As(err, &dnsErr):
+		return reasonDNS
+	}
+
+	if reason := getTLSErrorReason(err); reason != "" {
+		return reason
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return reasonTimeout
+
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return reasonRefused
+
+	case errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.EPIPE),
+		errors.Is(err, io.EOF),
+		errors.Is(err, io.ErrUnexpectedEOF):
+		return reasonReset
+
+	case errors.As(err, &urlErr) && urlErr.Op == "parse",
+		strings.Contains(err.Error(), "unsupported protocol scheme"),
+		strings.Contains(err.Error(), "no Host in request URL"):
+		return reasonInvalidURL
+	}
+
+	return reasonOther
+}
+
+// getTLSErrorReason returns the reason of the certificate or TLS handshake error, or an empty string for other errors.
+func getTLSErrorReason(err error) string {
+	var (
+		certErr      *tls.CertificateVerificationError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+		recordErr    tls.RecordHeaderError
+		alertErr     tls.AlertError
+		opErr        *net.OpError
+	)
+
+	switch {
+	case errors.As(err, &hostnameErr):
+		return reasonHostnameMismatch
+
+	case errors.As(err, &invalidErr) && invalidErr.Reason == x509.Expired:
+		return reasonExpired
+
+	case errors.As(err, &authorityErr):
+		return reasonUnknownAuthority
+
+	case errors.As(err, &invalidErr), errors.As(err, &certErr):
+		return reasonInvalidCert
+
+	case errors.As(err, &recordErr),
+		errors.As(err, &alertErr),
+		errors.As(err, &opErr) && opErr.Op == "remote error", // TLS alert sent by the server.
+		strings.Contains(err.Error(), "tls: "):
+		return reasonTLS
+	}
+
+	return ""
+}
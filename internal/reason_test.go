@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetErrorReason(t *testing.T) {
+	_, parseErr := url.Parse("http://host/%zz")
+
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"nil", nil, ""},
+		{
+			"dns",
+			&url.Error{Op: "Get", URL: "http://nohost", Err: &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host"}}},
+			reasonDNS,
+		},
+		{
+			"connection refused",
+			&url.Error{Op: "Get", URL: "http://host", Err: &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}},
+			reasonRefused,
+		},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), reasonReset},
+		{"eof", &url.Error{Op: "Get", URL: "http://host", Err: io.EOF}, reasonReset},
+		{"unknown authority", &url.Error{Op: "Get", URL: "https://host", Err: x509.UnknownAuthorityError{}}, reasonUnknownAuthority},
+		{"hostname mismatch", &tls.CertificateVerificationError{Err: x509.HostnameError{}}, reasonHostnameMismatch},
+		{"expired", &tls.CertificateVerificationError{Err: x509.CertificateInvalidError{Reason: x509.Expired}}, reasonExpired},
+		{"invalid certificate", x509.CertificateInvalidError{Reason: x509.NotAuthorizedToSign}, reasonInvalidCert},
+		{"tls alert", &url.Error{Op: "Get", URL: "https://host", Err: tls.AlertError(40)}, reasonTLS},
+		{"timeout", &url.Error{Op: "Get", URL: "http://host", Err: context.DeadlineExceeded}, reasonTimeout},
+		{"dial timeout", &net.OpError{Op: "dial", Err: &timeoutError{}}, reasonTimeout},
+		{"parse", parseErr, reasonInvalidURL},
+		{"unsupported scheme", &url.Error{Op: "Get", URL: "ftp://host", Err: errors.New(`unsupported protocol scheme "ftp"`)}, reasonInvalidURL},
+		{"too many redirects", &url.Error{Op: "Get", URL: "http://host", Err: ErrTooManyRedirects}, reasonTooManyRedirects},
+		{"other", errors.New("other"), reasonOther},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, getErrorReason(test.err))
+		})
+	}
+}
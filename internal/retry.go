@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// maxRetryAfterSeconds limits parsed Retry-After values to avoid durations overflow.
+const maxRetryAfterSeconds = 24 * 60 * 60
+
+// isTransientError checks whether the request error is likely to disappear on retry.
+func isTransientError(err error) bool {
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.EPIPE),
+		errors.Is(err, io.EOF),
+		errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return true
+	}
+
+	return false
+}
+
+// isRetryableResponse checks whether the response status code is listed in inspector.retryStatusCodes.
+func (i *defaultInspector) isRetryableResponse(resp *http.Response) bool {
+	return containsCode(i.cfg.RetryStatusCodes, resp.StatusCode)
+}
+
+// getRetryDelay returns the delay before the next attempt.
+// Delay is the Retry-After response header value, if any, or the exponential backoff with jitter.
+// It does not exceed inspector.retryMaxDelay.
+func (i *defaultInspector) getRetryDelay(attempt byte, resp *http.Response) time.Duration {
+	var delay time.Duration
+
+	if resp != nil {
+		delay = parseRetryAfter(resp.Header.Get("Retry-After"), i.deps.getNow()())
+	}
+
+	if delay == 0 {
+		backoff := i.cfg.RetryDelay
+		for n := byte(0); n < attempt && backoff < math.MaxInt64/2; n++ {
+			backoff *= 2
+		}
+
+		if i.cfg.RetryMaxDelay > 0 {
+			backoff = min(backoff, i.cfg.RetryMaxDelay)
+		}
+
+		// equal jitter keeps at least a half of the backoff.
+		delay = backoff/2 + rand.N(backoff/2+1) //nolint:gosec // jitter does not require secure random numbers.
+	}
+
+	if i.cfg.RetryMaxDelay > 0 {
+		delay = min(delay, i.cfg.RetryMaxDelay)
+	}
+
+	return delay
+}
+
+// parseRetryAfter parses the Retry-After header value, either a number of seconds or an HTTP date.
+// Zero is returned for empty, invalid and past values.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(min(max(seconds, 0), maxRetryAfterSeconds)) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+
+	return 0
+}
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"broken pipe", syscall.EPIPE, true},
+		{"eof", &url.Error{Op: "Get", URL: "http://host", Err: io.EOF}, true},
+		{"unexpected eof", io.ErrUnexpectedEOF, true},
+		{"timeout", &url.Error{Op: "Get", URL: "http://host", Err: context.DeadlineExceeded}, true},
+		{"dial timeout", &net.OpError{Op: "dial", Err: &timeoutError{}}, true},
+		{"connection refused", syscall.ECONNREFUSED, false},
+		{"too many redirects", ErrTooManyRedirects, false},
+		{"other", errors.New("other"), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, isTransientError(test.err))
+		})
+	}
+}
+
+type timeoutError struct{}
+
+func (e *timeoutError) Error() string   { return "i/o timeout" }
+func (e *timeoutError) Timeout() bool   { return true }
+func (e *timeoutError) Temporary() bool { return true }
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{" 5 ", 5 * time.Second},
+		{"-5", 0},
+		{"99999999999", 24 * time.Hour},
+		{"Fri, 02 Jan 2026 03:05:05 GMT", time.Minute},
+		{"Fri, 02 Jan 2026 03:03:05 GMT", 0},
+		{"invalid", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			require.Equal(t, test.expected, parseRetryAfter(test.value, now))
+		})
+	}
+}
+
+func TestGetRetryDelay(t *testing.T) {
+	i := &defaultInspector{
+		cfg: &inspectorConfig{RetryDelay: 100 * time.Millisecond, RetryMaxDelay: time.Second},
+	}
+
+	for attempt, expectedMax := range []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	} {
+		for range 10 {
+			d := i.getRetryDelay(byte(attempt), nil)
+			require.GreaterOrEqual(t, d, expectedMax/2, attempt)
+			require.LessOrEqual(t, d, expectedMax, attempt)
+		}
+	}
+
+	// backoff does not overflow.
+	d := i.getRetryDelay(255, nil)
+	require.GreaterOrEqual(t, d, 500*time.Millisecond)
+	require.LessOrEqual(t, d, time.Second)
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"1"}}}
+	i.cfg.RetryMaxDelay = 0
+	require.Equal(t, time.Second, i.getRetryDelay(0, resp))
+
+	// Retry-After does not exceed the maximum delay.
+	i.cfg.RetryMaxDelay = 500 * time.Millisecond
+	require.Equal(t, 500*time.Millisecond, i.getRetryDelay(0, resp))
+}
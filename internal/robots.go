@@ -0,0 +1,282 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	robotsPath = "/robots.txt"
+
+	// maxRobotsSize is the maximum parsed robots.txt size, as required by RFC 9309.
+	maxRobotsSize = 500 << 10
+)
+
+// robots holds robots.txt rules applying to the inspector user agent.
+// Nil robots allow everything.
+type robots struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+	sitemaps   []string
+}
+
+// robotsRule is an allow or disallow robots.txt rule.
+type robotsRule struct {
+	pattern string
+	allow   bool
+}
+
+// robotsGroup is a robots.txt group of rules applying to the listed user agents.
+type robotsGroup struct {
+	userAgents []string
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+// disallowAll is used in case robots.txt is unavailable because of a server error.
+var disallowAll = &robots{rules: []robotsRule{{pattern: "/"}}}
+
+// robotsParser holds robots.txt parsing state.
+type robotsParser struct {
+	groups   []*robotsGroup
+	current  *robotsGroup
+	inRules  bool
+	sitemaps []string
+}
+
+// parseRobots parses robots.txt content and returns rules applying to the given user agent product token.
+// Rules of groups matching the token are merged. In case there are none, rules of the "*" group are used.
+func parseRobots(r io.Reader, token string) *robots {
+	p := robotsParser{}
+
+	scanner := bufio.NewScanner(io.LimitReader(r, maxRobotsSize))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+
+		p.parseLine(strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value))
+	}
+
+	rb := &robots{sitemaps: p.sitemaps}
+	for _, g := range selectRobotsGroups(p.groups, token) {
+		rb.rules = append(rb.rules, g.rules...)
+		rb.crawlDelay = max(rb.crawlDelay, g.crawlDelay)
+	}
+
+	return rb
+}
+
+// parseLine applies the robots.txt line with the given lowercased key and value to the parsing state.
+func (p *robotsParser) parseLine(key, value string) {
+	switch key {
+	case "user-agent":
+		// consecutive user-agent lines start a single group.
+		if p.current == nil || p.inRules {
+			p.current = &robotsGroup{}
+			p.groups = append(p.groups, p.current)
+			p.inRules = false
+		}
+
+		p.current.userAgents = append(p.current.userAgents, strings.ToLower(value))
+
+	case "allow", "disallow":
+		if p.current == nil {
+			return
+		}
+
+		p.inRules = true
+
+		// empty disallow rule allows everything.
+		if value != "" {
+			p.current.rules = append(p.current.rules, robotsRule{pattern: value, allow: key == "allow"})
+		}
+
+	case "crawl-delay":
+		if p.current == nil {
+			return
+		}
+
+		p.inRules = true
+
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+			p.current.crawlDelay = time.Duration(seconds * float64(time.Second))
+		}
+
+	case "sitemap":
+		// sitemap lines do not belong to groups.
+		if value != "" {
+			p.sitemaps = append(p.sitemaps, value)
+		}
+	}
+}
+
+// selectRobotsGroups returns groups applying to the given user agent product token.
+// Groups of the "*" user agent are returned in case no group matches the token.
+func selectRobotsGroups(groups []*robotsGroup, token string) []*robotsGroup {
+	token = strings.ToLower(token)
+
+	var matching, wildcard []*robotsGroup
+
+	for _, g := range groups {
+		for _, ua := range g.userAgents {
+			switch ua {
+			case token:
+				matching = append(matching, g)
+
+			case "*":
+				wildcard = append(wildcard, g)
+
+			default:
+				continue
+			}
+
+			break
+		}
+	}
+
+	if len(matching) == 0 {
+		return wildcard
+	}
+
+	return matching
+}
+
+// isAllowed checks whether the URL path with query is allowed to be crawled.
+// The longest matching rule wins, allow rule wins in case of equal length.
+func (rb *robots) isAllowed(u *url.URL) bool {
+	if rb == nil || u.Path == robotsPath {
+		return true
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+
+	allowed, matchedLength := true, -1
+
+	for _, r := range rb.rules {
+		if !matchRobotsPattern(r.pattern, path) {
+			continue
+		}
+
+		if len(r.pattern) > matchedLength || (len(r.pattern) == matchedLength && r.allow) {
+			allowed, matchedLength = r.allow, len(r.pattern)
+		}
+	}
+
+	return allowed
+}
+
+// matchRobotsPattern checks whether the path matches the robots.txt rule pattern.
+// Pattern matches path prefix, "*" matches any sequence of characters, trailing "$" matches the path end.
+func matchRobotsPattern(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	if anchored {
+		pattern = pattern[:len(pattern)-1]
+	}
+
+	parts := strings.Split(pattern, "*")
+
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+
+	rest := path[len(parts[0]):]
+
+	for idx, part := range parts[1:] {
+		// the last part of an anchored pattern must match the path end.
+		if anchored && idx == len(parts)-2 {
+			return strings.HasSuffix(rest, part)
+		}
+
+		pos := strings.Index(rest, part)
+		if pos < 0 {
+			return false
+		}
+
+		rest = rest[pos+len(part):]
+	}
+
+	return !anchored || rest == ""
+}
+
+// getUserAgentToken returns the user agent product token, like "links" for "links/v1.0.0".
+func getUserAgentToken(userAgent string) string {
+	token, _, _ := strings.Cut(userAgent, "/")
+	token, _, _ = strings.Cut(token, " ")
+
+	return token
+}
+
+// hostRobots holds a host robots.txt, requested once.
+type hostRobots struct {
+	once   sync.Once
+	robots *robots
+}
+
+// getRobots requests and parses robots.txt of the given URL host.
+// Missing robots.txt allows everything, robots.txt unavailable because of a server error disallows everything.
+func (i *defaultInspector) getRobots(ctx context.Context, base *url.URL) *robots {
+	u := base.ResolveReference(&url.URL{Path: robotsPath}).String()
+
+	l := i.get(ctx, i.httpClient, u)
+	if l.body != nil {
+		defer l.body.Close()
+	}
+
+	switch {
+	case l.err != nil:
+		// host pages requests are going to fail as well.
+		return nil
+
+	case l.code >= http.StatusInternalServerError:
+		_, _ = i.deps.getErrPrintFn()(u, "is unavailable, all paths are disallowed, status:", l.code)
+		return disallowAll
+
+	case l.code >= http.StatusBadRequest || l.body == nil:
+		return nil
+	}
+
+	return parseRobots(l.body, getUserAgentToken(i.cfg.getUserAgent()))
+}
+
+// loadRobots requests robots.txt of the given URL scheme and host once and returns it.
+// With inspector.ignoreRobotsTxt, robots.txt is requested only for discovering sitemaps.
+func (i *defaultInspector) loadRobots(ctx context.Context, u *url.URL) *robots {
+	v, _ := i.robots.LoadOrStore(u.Scheme+"://"+u.Host, &hostRobots{})
+	hr := v.(*hostRobots)
+
+	hr.once.Do(func() {
+		hr.robots = i.getRobots(ctx, u)
+		if hr.robots != nil && !i.cfg.IgnoreRobotsTxt {
+			i.limiter.setCrawlDelay(u.Host, hr.robots.crawlDelay)
+		}
+	})
+
+	return hr.robots
+}
+
+// isAllowedByRobots checks whether the internal URL is allowed to be crawled by its host robots.txt.
+func (i *defaultInspector) isAllowedByRobots(ctx context.Context, u *url.URL) bool {
+	if i.cfg.IgnoreRobotsTxt {
+		return true
+	}
+
+	return i.loadRobots(ctx, u).isAllowed(u)
+}
@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var robotsTxt = `# robots.txt
+User-agent: *
+Disallow: /private
+Allow: /private/public
+Crawl-delay: 1
+
+User-agent: links
+User-agent: otherbot
+Disallow: /admin/   # administration pages
+Disallow: /*.pdf$
+Disallow: /search?q=*&page=
+Allow: /admin/help
+Crawl-delay: 0.5
+
+User-agent: thirdbot
+Disallow:
+`
+
+func TestParseRobots(t *testing.T) {
+	tests := []struct {
+		name               string
+		token              string
+		expectedAllowed    []string
+		expectedDisallowed []string
+		expectedCrawlDelay time.Duration
+	}{
+		{
+			name:  "matching group",
+			token: "Links",
+			expectedAllowed: []string{
+				"/", "/private", "/admin", "/admin/help", "/admin/help/topic", "/file.pdf?download", "/search?q=go",
+				"/robots.txt",
+			},
+			expectedDisallowed: []string{"/admin/", "/admin/users", "/file.pdf", "/docs/file.pdf", "/search?q=go&page=2"},
+			expectedCrawlDelay: 500 * time.Millisecond,
+		},
+
+		{
+			name:               "wildcard group",
+			token:              "unknown",
+			expectedAllowed:    []string{"/", "/admin/", "/private/public", "/private/public/page"},
+			expectedDisallowed: []string{"/private", "/private/page", "/privateer"},
+			expectedCrawlDelay: time.Second,
+		},
+
+		{
+			name:            "empty disallow",
+			token:           "thirdbot",
+			expectedAllowed: []string{"/", "/private", "/admin/"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rb := parseRobots(strings.NewReader(robotsTxt), test.token)
+
+			for _, path := range test.expectedAllowed {
+				u, err := url.Parse("http://host" + path)
+				require.NoError(t, err)
+				require.True(t, rb.isAllowed(u), path)
+			}
+
+			for _, path := range test.expectedDisallowed {
+				u, err := url.Parse("http://host" + path)
+				require.NoError(t, err)
+				require.False(t, rb.isAllowed(u), path)
+			}
+
+			require.Equal(t, test.expectedCrawlDelay, rb.crawlDelay)
+		})
+	}
+}
+
+func TestMatchRobotsPattern(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"/", "/", true},
+		{"/fish", "/fish.html", true},
+		{"/fish", "/Fish.html", false},
+		{"/fish/", "/fish", false},
+		{"/*.php", "/folder/filename.php?parameters", true},
+		{"/*.php$", "/filename.php", true},
+		{"/*.php$", "/filename.php?parameters", false},
+		{"/*.php$", "/filename.php5", false},
+		{"/fish*.php", "/fishheads/catfish.php?parameters", true},
+		{"/fish*.php", "/Fish.PHP", false},
+		{"/a*b*c$", "/axbxcxc", true},
+		{"/a*b*c$", "/axbxcx", false},
+		{"/page$", "/page", true},
+		{"/page$", "/page/", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern+" "+test.path, func(t *testing.T) {
+			require.Equal(t, test.expected, matchRobotsPattern(test.pattern, test.path))
+		})
+	}
+}
+
+func TestGetUserAgentToken(t *testing.T) {
+	require.Equal(t, "links", getUserAgentToken("links/v1.0.0"))
+	require.Equal(t, "Mozilla", getUserAgentToken("Mozilla/5.0 (X11; Linux x86_64)"))
+	require.Equal(t, "checker", getUserAgentToken("checker (+https://example.com)"))
+}
+
+func TestParseRobots_Sitemaps(t *testing.T) {
+	rb := parseRobots(
+		strings.NewReader("Sitemap: http://host/sitemap.xml\nUser-agent: *\nDisallow: /private\nSitemap: /other.xml\n"),
+		"links",
+	)
+
+	require.Equal(t, []string{"http://host/sitemap.xml", "/other.xml"}, rb.sitemaps)
+}
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/ygrebnov/errorc"
+)
+
+// regexpPatternPrefix is a prefix of URL patterns defined by regular expressions.
+const regexpPatternPrefix = "re:"
+
+// scope defines inspected host URLs, which are in the inspection scope.
+type scope struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// newScope creates a scope from the given include and exclude URL patterns.
+func newScope(include, exclude []string) (*scope, error) {
+	inc, err := compilePatterns(include, ErrInvalidInspectorIncludeValue)
+	if err != nil {
+		return nil, err
+	}
+
+	exc, err := compilePatterns(exclude, ErrInvalidInspectorExcludeValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return &scope{include: inc, exclude: exc}, nil
+}
+
+// contains checks whether the URL path with query matches any include pattern and none of exclude patterns.
+// All URLs match, if there are no include patterns.
+func (s *scope) contains(u *url.URL) bool {
+	target := u.EscapedPath()
+	if target == "" {
+		target = "/"
+	}
+
+	if u.RawQuery != "" {
+		target += "?" + u.RawQuery
+	}
+
+	for _, re := range s.exclude {
+		if re.MatchString(target) {
+			return false
+		}
+	}
+
+	if len(s.include) == 0 {
+		return true
+	}
+
+	for _, re := range s.include {
+		if re.MatchString(target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compilePatterns compiles URL patterns, globs or regular expressions prefixed with "re:".
+func compilePatterns(patterns []string, errInvalid error) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+
+	for _, p := range patterns {
+		expr := globToRegexp(p)
+		if strings.HasPrefix(p, regexpPatternPrefix) {
+			expr = strings.TrimPrefix(p, regexpPatternPrefix)
+		}
+
+		re, err := regexp.Compile(expr)
+		if p == "" || err != nil {
+			return nil, errorc.With(errInvalid, errorc.Field("value", p))
+		}
+
+		compiled = append(compiled, re)
+	}
+
+	return compiled, nil
+}
+
+// globToRegexp converts a glob to an anchored regular expression.
+// "**" matches any sequence of characters, "*" matches any sequence of characters except "/",
+// "?" matches any single character except "/".
+func globToRegexp(glob string) string {
+	var b strings.Builder
+
+	b.WriteString("^")
+
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+
+		case glob[i] == '*':
+			b.WriteString("[^/]*")
+
+		case glob[i] == '?':
+			b.WriteString("[^/]")
+
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+
+	b.WriteString("$")
+
+	return b.String()
+}
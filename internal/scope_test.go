@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScope(t *testing.T) {
+	tests := []struct {
+		name        string
+		include     []string
+		exclude     []string
+		expectedIn  []string
+		expectedOut []string
+		expectedErr error
+	}{
+		{
+			name:       "no patterns",
+			expectedIn: []string{"", "/", "/docs/page", "/api/v1?q=1"},
+		},
+
+		{
+			name:        "include glob",
+			include:     []string{"/docs/**"},
+			expectedIn:  []string{"/docs/", "/docs/page", "/docs/guide/install?lang=en"},
+			expectedOut: []string{"/", "/docs", "/api/docs/page"},
+		},
+
+		{
+			name:        "single segment glob",
+			include:     []string{"/docs/*", "/page-?"},
+			expectedIn:  []string{"/docs/page", "/page-1"},
+			expectedOut: []string{"/docs/guide/install", "/page-10", "/page-/"},
+		},
+
+		{
+			name:        "exclude",
+			include:     []string{"/**"},
+			exclude:     []string{"/api/**", "re:^/calendar\\?.*year=\\d+"},
+			expectedIn:  []string{"/", "/docs/page", "/calendar", "/calendar?month=1"},
+			expectedOut: []string{"/api/", "/api/v1/users", "/calendar?year=2024&month=1"},
+		},
+
+		{
+			name:        "escaped characters",
+			include:     []string{"/docs/v1.0/**"},
+			expectedIn:  []string{"/docs/v1.0/page"},
+			expectedOut: []string{"/docs/v100/page"},
+		},
+
+		{
+			name:        "invalid include",
+			include:     []string{"re:("},
+			expectedErr: ErrInvalidInspectorIncludeValue,
+		},
+
+		{
+			name:        "empty exclude",
+			exclude:     []string{""},
+			expectedErr: ErrInvalidInspectorExcludeValue,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := newScope(test.include, test.exclude)
+			if test.expectedErr != nil {
+				require.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			for _, target := range test.expectedIn {
+				u, err := url.Parse("http://host" + target)
+				require.NoError(t, err)
+				require.True(t, s.contains(u), target)
+			}
+
+			for _, target := range test.expectedOut {
+				u, err := url.Parse("http://host" + target)
+				require.NoError(t, err)
+				require.False(t, s.contains(u), target)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ygrebnov/errorc"
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	// httpOnlyCookiePrefix prefixes HttpOnly cookies lines in Netscape cookies files.
+	httpOnlyCookiePrefix = "#HttpOnly_"
+
+	// maxLoginResponseSize limits the login response body size searched for the success text.
+	maxLoginResponseSize = 10 << 20
+)
+
+// fileCookie is a cookie read from a cookies file along with the URL it is set for.
+type fileCookie struct {
+	url    *url.URL
+	cookie *http.Cookie
+}
+
+// newCookieJar creates a cookie jar holding the inspected host session.
+// The jar is seeded with cookies from inspector.cookies and inspector.cookiesFile.
+func newCookieJar(cfg *inspectorConfig) (http.CookieJar, error) {
+	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL, err := url.Parse(cfg.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range cfg.Cookies {
+		value, err := resolveSecret(c.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		u := *baseURL
+		if c.Domain != "" {
+			u.Host = strings.TrimPrefix(c.Domain, ".")
+		}
+
+		path := c.Path
+		if path == "" {
+			path = "/"
+		}
+
+		jar.SetCookies(&u, []*http.Cookie{{Name: c.Name, Value: value, Domain: c.Domain, Path: path}})
+	}
+
+	if cfg.CookiesFile == "" {
+		return jar, nil
+	}
+
+	f, err := os.Open(cfg.CookiesFile)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	cookies, err := parseCookiesFile(f)
+	if err != nil {
+		return nil, errorc.With(err, errorc.Field("path", cfg.CookiesFile))
+	}
+
+	for _, c := range cookies {
+		jar.SetCookies(c.url, []*http.Cookie{c.cookie})
+	}
+
+	return jar, nil
+}
+
+// parseCookiesFile parses cookies in the Netscape cookies file format, used by curl and browsers extensions.
+// Each line holds tab separated domain, subdomains flag, path, secure flag, expiration time, name and value.
+func parseCookiesFile(r io.Reader) ([]fileCookie, error) {
+	var cookies []fileCookie
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		httpOnly := strings.HasPrefix(line, httpOnlyCookiePrefix)
+		line = strings.TrimPrefix(line, httpOnlyCookiePrefix)
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return nil, errorc.With(ErrInvalidCookiesFile, errorc.Field("line", strconv.Itoa(n)))
+		}
+
+		expires, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, errorc.With(ErrInvalidCookiesFile, errorc.Field("line", strconv.Itoa(n)))
+		}
+
+		c := &http.Cookie{
+			Name:     fields[5],
+			Value:    fields[6],
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			HttpOnly: httpOnly,
+		}
+
+		// cookies without subdomains flag are host-only cookies.
+		if strings.EqualFold(fields[1], "TRUE") {
+			c.Domain = fields[0]
+		}
+
+		// zero expiration time stands for a session cookie.
+		if expires > 0 {
+			c.Expires = time.Unix(expires, 0)
+		}
+
+		scheme := "http"
+		if c.Secure {
+			scheme = "https"
+		}
+
+		cookies = append(cookies, fileCookie{
+			url:    &url.URL{Scheme: scheme, Host: strings.TrimPrefix(fields[0], "."), Path: c.Path},
+			cookie: c,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return cookies, nil
+}
+
+// login submits the form configured in inspector.login, so that the session cookies are stored in the client jar.
+// There is no session to log in to in offline mode.
+func login(ctx context.Context, client *http.Client, cfg *inspectorConfig) error {
+	if cfg.Login == nil || cfg.Dir != "" {
+		return nil
+	}
+
+	baseURL, err := url.Parse(cfg.Host)
+	if err != nil {
+		return err
+	}
+
+	req, err := newLoginRequest(ctx, cfg, baseURL)
+	if err != nil {
+		return err
+	}
+
+	loginURL := req.URL.String()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errorc.With(ErrLoginFailed, errorc.Field("url", loginURL), errorc.Field("reason", err.Error()))
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	return checkLoginResponse(resp, loginURL, client.Jar, baseURL, cfg.Login)
+}
+
+// newLoginRequest creates the login form request with fields values resolved from secrets, if needed.
+// Login URL is resolved against the inspected host.
+func newLoginRequest(ctx context.Context, cfg *inspectorConfig, baseURL *url.URL) (*http.Request, error) {
+	ref, err := url.Parse(cfg.Login.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	form := make(url.Values, len(cfg.Login.Fields))
+	for _, f := range cfg.Login.Fields {
+		value, err := resolveSecret(f.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		form.Add(f.Name, value)
+	}
+
+	loginURL := baseURL.ResolveReference(ref).String()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, loginURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", cfg.getUserAgent())
+
+	return req, nil
+}
+
+// checkLoginResponse checks the login response status code, and the success text and cookie, if configured.
+func checkLoginResponse(
+	resp *http.Response,
+	loginURL string,
+	jar http.CookieJar,
+	baseURL *url.URL,
+	cfg *loginConfig,
+) error {
+	if resp.StatusCode >= http.StatusBadRequest {
+		return errorc.With(
+			ErrLoginFailed,
+			errorc.Field("url", loginURL),
+			errorc.Field("status", strconv.Itoa(resp.StatusCode)),
+		)
+	}
+
+	if cfg.SuccessText != "" {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxLoginResponseSize))
+		if err != nil {
+			return errorc.With(ErrLoginFailed, errorc.Field("url", loginURL), errorc.Field("reason", err.Error()))
+		}
+
+		if !bytes.Contains(body, []byte(cfg.SuccessText)) {
+			return errorc.With(
+				ErrLoginFailed,
+				errorc.Field("url", loginURL),
+				errorc.Field("reason", "success text not found"),
+			)
+		}
+	}
+
+	if cfg.SuccessCookie != "" && !hasCookie(jar, baseURL, cfg.SuccessCookie) {
+		return errorc.With(
+			ErrLoginFailed,
+			errorc.Field("url", loginURL),
+			errorc.Field("reason", "success cookie not set"),
+		)
+	}
+
+	return nil
+}
+
+// hasCookie checks whether the jar holds a cookie with the given name for the given URL.
+func hasCookie(jar http.CookieJar, u *url.URL, name string) bool {
+	if jar == nil {
+		return false
+	}
+
+	for _, c := range jar.Cookies(u) {
+		if c.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,214 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCookiesFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		expected    []fileCookie
+		expectedErr error
+	}{
+		{
+			name: "nominal",
+			data: "# Netscape HTTP Cookie File\n" +
+				"\n" +
+				".example.com\tTRUE\t/\tFALSE\t0\tsession\tabc\n" +
+				"#HttpOnly_example.com\tFALSE\t/app\tTRUE\t1767225600\ttoken\txyz\n",
+			expected: []fileCookie{
+				{
+					url:    &url.URL{Scheme: "http", Host: "example.com", Path: "/"},
+					cookie: &http.Cookie{Name: "session", Value: "abc", Domain: ".example.com", Path: "/"},
+				},
+				{
+					url: &url.URL{Scheme: "https", Host: "example.com", Path: "/app"},
+					cookie: &http.Cookie{
+						Name:     "token",
+						Value:    "xyz",
+						Path:     "/app",
+						Secure:   true,
+						HttpOnly: true,
+						Expires:  time.Unix(1767225600, 0),
+					},
+				},
+			},
+		},
+
+		{
+			name:        "invalid fields number",
+			data:        "example.com\tFALSE\t/\tFALSE\t0\tsession\n",
+			expectedErr: ErrInvalidCookiesFile,
+		},
+
+		{
+			name:        "invalid expiration time",
+			data:        "example.com\tFALSE\t/\tFALSE\tnever\tsession\tabc\n",
+			expectedErr: ErrInvalidCookiesFile,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cookies, err := parseCookiesFile(strings.NewReader(test.data))
+			if test.expectedErr != nil {
+				require.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expected, cookies)
+		})
+	}
+}
+
+func TestNewCookieJar(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "cookies.txt")
+	require.NoError(t, os.WriteFile(file, []byte("localhost\tFALSE\t/\tFALSE\t0\tfromFile\tfile\n"), 0o600))
+
+	t.Setenv("LINKS_TEST_SESSION", "env")
+
+	jar, err := newCookieJar(&inspectorConfig{
+		Host: "http://localhost:8080",
+		Cookies: []cookieConfig{
+			{Name: "fromConfig", Value: "env:LINKS_TEST_SESSION"},
+			{Name: "admin", Value: "admin", Path: "/admin"},
+		},
+		CookiesFile: file,
+	})
+	require.NoError(t, err)
+
+	u, err := url.Parse("http://localhost:8080/page")
+	require.NoError(t, err)
+
+	cookies := make(map[string]string)
+	for _, c := range jar.Cookies(u) {
+		cookies[c.Name] = c.Value
+	}
+
+	require.Equal(t, map[string]string{"fromConfig": "env", "fromFile": "file"}, cookies)
+
+	_, err = newCookieJar(&inspectorConfig{Host: "http://localhost", CookiesFile: filepath.Join(t.TempDir(), "missing")})
+	require.Error(t, err)
+}
+
+func TestLogin(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /login", func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.PostFormValue("username") != "user":
+			w.WriteHeader(http.StatusUnauthorized)
+
+		case r.PostFormValue("password") != "password":
+			_, _ = w.Write([]byte("Invalid credentials"))
+
+		default:
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+			http.Redirect(w, r, "/home", http.StatusFound)
+		}
+	})
+	mux.HandleFunc("GET /home", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("session"); err != nil {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		_, _ = w.Write([]byte("Welcome"))
+	})
+
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	t.Setenv("LINKS_TEST_PASSWORD", "password")
+
+	tests := []struct {
+		name        string
+		login       *loginConfig
+		expectedErr string
+	}{
+		{
+			name: "nominal",
+			login: &loginConfig{
+				URL:           "/login",
+				Fields:        []formField{{"username", "user"}, {"password", "env:LINKS_TEST_PASSWORD"}},
+				SuccessText:   "Welcome",
+				SuccessCookie: "session",
+			},
+		},
+
+		{
+			name: "no login",
+		},
+
+		{
+			name: "error status",
+			login: &loginConfig{
+				URL:    s.URL + "/login",
+				Fields: []formField{{"username", "other"}},
+			},
+			expectedErr: "status: 401",
+		},
+
+		{
+			name: "success text not found",
+			login: &loginConfig{
+				URL:         "/login",
+				Fields:      []formField{{"username", "user"}, {"password", "invalid"}},
+				SuccessText: "Welcome",
+			},
+			expectedErr: "success text not found",
+		},
+
+		{
+			name: "success cookie not set",
+			login: &loginConfig{
+				URL:           "/login",
+				Fields:        []formField{{"username", "user"}, {"password", "invalid"}},
+				SuccessCookie: "session",
+			},
+			expectedErr: "success cookie not set",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &inspectorConfig{Host: s.URL, Login: test.login}
+
+			client, err := newHTTPClient(cfg, false)
+			require.NoError(t, err)
+
+			err = login(context.Background(), client, cfg)
+			if test.expectedErr != "" {
+				require.ErrorIs(t, err, ErrLoginFailed)
+				require.ErrorContains(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			if test.login == nil {
+				return
+			}
+
+			// session is reused for subsequent requests.
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, s.URL+"/home", http.NoBody)
+			require.NoError(t, err)
+
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package internal
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+const (
+	// sitemapAuto is an inspector.sitemap value for discovering sitemaps from robots.txt.
+	sitemapAuto = "auto"
+
+	defaultSitemapPath = "/sitemap.xml"
+
+	// maxSitemapSize is the maximum uncompressed sitemap size, as defined by the sitemaps protocol.
+	maxSitemapSize = 50 << 20
+
+	// maxSitemapDepth is the maximum nesting level of sitemap indexes.
+	maxSitemapDepth = 3
+
+	sitemapIssueOrphan   = "orphan"
+	sitemapIssueUnlisted = "unlisted"
+)
+
+// sitemapLocation is a location element of a sitemap or a sitemap index entry.
+type sitemapLocation struct {
+	Loc string `xml:"loc"`
+}
+
+// sitemapDocument is a sitemap or a sitemap index.
+type sitemapDocument struct {
+	URLs     []sitemapLocation `xml:"url"`
+	Sitemaps []sitemapLocation `xml:"sitemap"`
+}
+
+// parseSitemap parses a sitemap or a sitemap index, gzipped or not.
+func parseSitemap(r io.Reader) (*sitemapDocument, error) {
+	br := bufio.NewReader(r)
+
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+
+		r = gr
+	} else {
+		r = br
+	}
+
+	var doc sitemapDocument
+	if err := xml.NewDecoder(io.LimitReader(r, maxSitemapSize)).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	return &doc, nil
+}
+
+// seedFromSitemap adds tasks requesting the inspected host pages listed in sitemaps.
+func (i *defaultInspector) seedFromSitemap(ctx context.Context) {
+	defer i.wg.Done()
+
+	locations := []string{i.cfg.Sitemap}
+	if i.cfg.Sitemap == sitemapAuto {
+		locations = []string{defaultSitemapPath}
+		if rb := i.loadRobots(ctx, i.baseURL); rb != nil && len(rb.sitemaps) > 0 {
+			locations = rb.sitemaps
+		}
+	}
+
+	visited := make(map[string]struct{})
+	for _, loc := range locations {
+		i.readSitemap(ctx, loc, 0, visited)
+	}
+}
+
+// readSitemap requests the sitemap at the given location and seeds inspection with listed pages.
+// Sitemaps listed in a sitemap index are read recursively.
+func (i *defaultInspector) readSitemap(ctx context.Context, loc string, depth int, visited map[string]struct{}) {
+	u, err := i.baseURL.Parse(loc)
+	if err != nil {
+		_, _ = i.deps.getErrPrintFn()(fmt.Errorf("error reading sitemap: %s: %w", loc, err))
+		return
+	}
+
+	if _, ok := visited[u.String()]; ok || depth > maxSitemapDepth {
+		return
+	}
+	visited[u.String()] = struct{}{}
+
+	l := i.get(ctx, i.httpClient, u.String())
+	if l.body != nil {
+		defer l.body.Close()
+	}
+
+	switch {
+	case l.err != nil:
+		_, _ = i.deps.getErrPrintFn()(fmt.Errorf("error reading sitemap: %s: %w", u, l.err))
+		return
+
+	case l.code >= http.StatusBadRequest || l.body == nil:
+		_, _ = i.deps.getErrPrintFn()("error reading sitemap:", u.String()+", status:", l.code)
+		return
+	}
+
+	doc, err := parseSitemap(l.body)
+	if err != nil {
+		_, _ = i.deps.getErrPrintFn()(fmt.Errorf("error reading sitemap: %s: %w", u, err))
+		return
+	}
+
+	for _, s := range doc.Sitemaps {
+		i.readSitemap(ctx, s.Loc, depth+1, visited)
+	}
+
+	for _, entry := range doc.URLs {
+		pageURL, err := i.baseURL.Parse(entry.Loc)
+		if err != nil || !i.isInternal(pageURL) {
+			continue // sitemaps may list only internal pages.
+		}
+
+		pageURL.Fragment, pageURL.RawFragment = "", ""
+
+		if _, listed := i.sitemapURLs.LoadOrStore(pageURL.String(), struct{}{}); listed {
+			continue
+		}
+
+		i.wg.Add(1)
+		_ = i.htmlProvider.AddTask(i.newGetHTMLTask(&foundLink{href: pageURL.String(), site: getOrigin(i.baseURL)}))
+	}
+}
+
+// checkSitemap marks orphan pages, listed in sitemap, but not referenced by any inspected page,
+// and unlisted pages, inspected, but not listed in sitemap.
+// Start pages are not orphans. Redirecting pages are not reported as unlisted.
+func (i *defaultInspector) checkSitemap(startURLs []string) {
+	if i.cfg.Sitemap == "" {
+		return
+	}
+
+	empty := true
+	i.sitemapURLs.Range(func(_, _ any) bool {
+		empty = false
+		return false
+	})
+
+	if empty {
+		return // sitemap has not been read.
+	}
+
+	starts := make(map[string]struct{}, len(startURLs))
+	for _, s := range startURLs {
+		if u, err := i.baseURL.Parse(s); err == nil {
+			starts[u.String()] = struct{}{}
+		}
+	}
+
+	i.visitedURLs.Range(func(_, value any) bool {
+		l := value.(*link)
+
+		_, listed := i.sitemapURLs.Load(l.URL)
+		_, inspected := i.anchors.Load(l.URL)
+		_, isStart := starts[l.URL]
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		switch {
+		case listed && !hasInboundReferrers(l) && !isStart:
+			l.SitemapIssue = sitemapIssueOrphan
+
+		case !listed && inspected && len(l.Redirects) == 0:
+			l.SitemapIssue = sitemapIssueUnlisted
+		}
+
+		return true
+	})
+}
+
+// hasInboundReferrers checks whether the link is referenced from pages other than the linked one.
+// Pages links to themselves, like canonical ones, do not make them reachable.
+func hasInboundReferrers(l *link) bool {
+	for _, r := range l.Referrers {
+		if r.URL != l.URL {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getSitemapIssues returns links with sitemap issues sorted by URL.
+func getSitemapIssues(data *sync.Map) []*link {
+	var issues []*link
+
+	data.Range(func(_, value any) bool {
+		if l := value.(*link); l.SitemapIssue != "" {
+			issues = append(issues, l)
+		}
+
+		return true
+	})
+
+	sort.Slice(issues, func(a, b int) bool {
+		return issues[a].URL < issues[b].URL
+	})
+
+	return issues
+}
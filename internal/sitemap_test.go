@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSitemap(t *testing.T) {
+	urlset := `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc>http://host/</loc><lastmod>2024-01-01</lastmod></url>
+	<url><loc>http://host/page</loc></url>
+</urlset>`
+
+	var gzipped bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	_, err := gw.Write([]byte(urlset))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	tests := []struct {
+		name        string
+		content     []byte
+		expected    *sitemapDocument
+		expectedErr bool
+	}{
+		{
+			name:     "urlset",
+			content:  []byte(urlset),
+			expected: &sitemapDocument{URLs: []sitemapLocation{{Loc: "http://host/"}, {Loc: "http://host/page"}}},
+		},
+
+		{
+			name:     "gzipped urlset",
+			content:  gzipped.Bytes(),
+			expected: &sitemapDocument{URLs: []sitemapLocation{{Loc: "http://host/"}, {Loc: "http://host/page"}}},
+		},
+
+		{
+			name: "sitemap index",
+			content: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<sitemap><loc>http://host/sitemap1.xml</loc></sitemap>
+	<sitemap><loc>http://host/sitemap2.xml.gz</loc></sitemap>
+</sitemapindex>`),
+			expected: &sitemapDocument{
+				Sitemaps: []sitemapLocation{{Loc: "http://host/sitemap1.xml"}, {Loc: "http://host/sitemap2.xml.gz"}},
+			},
+		},
+
+		{
+			name:        "invalid",
+			content:     []byte(`not a sitemap`),
+			expectedErr: true,
+		},
+
+		{
+			name:        "invalid gzip",
+			content:     []byte{0x1f, 0x8b, 0x00},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := parseSitemap(bytes.NewReader(test.content))
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expected, doc)
+		})
+	}
+}
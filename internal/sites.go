@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+)
+
+// defaultStartPath is the start path used, if no start URLs are given.
+const defaultStartPath = "/"
+
+// readStartURLsFile reads start URLs from the given file.
+func readStartURLsFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	return readURLs(f)
+}
+
+// readURLs reads URLs, one per line.
+// Empty lines and lines starting with "#" are skipped.
+func readURLs(r io.Reader) ([]string, error) {
+	var urls []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		urls = append(urls, line)
+	}
+
+	return urls, scanner.Err()
+}
+
+// getOrigin returns the URL scheme and host, identifying the site the URL belongs to.
+func getOrigin(u *url.URL) string {
+	return u.Scheme + "://" + u.Host
+}
+
+// getSites returns distinct origins of inspector.startURLs in order of appearance.
+// Relative start URLs belong to the inspected host.
+func getSites(cfg *inspectorConfig) []string {
+	baseURL, err := url.Parse(cfg.Host)
+	if err != nil {
+		return nil
+	}
+
+	startURLs := cfg.StartURLs
+	if len(startURLs) == 0 {
+		startURLs = []string{defaultStartPath}
+	}
+
+	sites := make([]string, 0, len(startURLs))
+	for _, s := range startURLs {
+		u, err := baseURL.Parse(s)
+		if err != nil {
+			continue
+		}
+
+		if site := getOrigin(u); !slices.Contains(sites, site) {
+			sites = append(sites, site)
+		}
+	}
+
+	return sites
+}
@@ -0,0 +1,29 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSites(t *testing.T) {
+	tests := []struct {
+		name      string
+		startURLs []string
+		expected  []string
+	}{
+		{name: "default start path", expected: []string{"http://host"}},
+		{name: "relative start URLs", startURLs: []string{"/start", "docs"}, expected: []string{"http://host"}},
+		{
+			name:      "several sites",
+			startURLs: []string{"https://other/docs", "/start", "https://other/blog", "http://host:8080/"},
+			expected:  []string{"https://other", "http://host", "http://host:8080"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, getSites(&inspectorConfig{Host: "http://host", StartURLs: test.startURLs}))
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package internal
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ygrebnov/errorc"
+)
+
+// failOnAny is an inspector.failOn value matching all broken links.
+const failOnAny = "any"
+
+// summary holds inspection results statistics.
+type summary struct {
+	Checked  int            `json:"checked"`
+	Broken   int            `json:"broken"`
+	Failed   int            `json:"failed"`
+	Statuses map[string]int `json:"statuses"`
+	Orphans  int            `json:"orphans,omitempty"`
+	Unlisted int            `json:"unlisted,omitempty"`
+}
+
+// newSummary computes statistics on the inspection results stored in data.
+// Links with status codes from inspector.skipStatusCodes are not taken into account.
+func newSummary(cfg *inspectorConfig, data *sync.Map) *summary {
+	s := &summary{Statuses: make(map[string]int)}
+
+	data.Range(func(_, value any) bool {
+		l := value.(*link)
+		if containsCode(cfg.SkipStatusCodes, l.code) {
+			return true
+		}
+
+		s.Checked++
+		s.Statuses[getStatusLabel(l.code)]++
+
+		if isBroken(l.code) {
+			s.Broken++
+		}
+
+		if isFailure(cfg.FailOn, l.code) {
+			s.Failed++
+		}
+
+		switch l.SitemapIssue {
+		case sitemapIssueOrphan:
+			s.Orphans++
+
+		case sitemapIssueUnlisted:
+			s.Unlisted++
+		}
+
+		return true
+	})
+
+	return s
+}
+
+// checkFailure returns an error in case the number of links matching inspector.failOn criteria
+// exceeds inspector.failThreshold.
+func checkFailure(cfg *inspectorConfig, data *sync.Map) error {
+	if len(cfg.FailOn) == 0 {
+		return nil
+	}
+
+	s := newSummary(cfg, data)
+	if s.Failed <= cfg.FailThreshold {
+		return nil
+	}
+
+	return errorc.With(
+		ErrInspectionFailed,
+		errorc.Field("failed", strconv.Itoa(s.Failed)),
+		errorc.Field("threshold", strconv.Itoa(cfg.FailThreshold)),
+		errorc.Field("broken", strconv.Itoa(s.Broken)),
+		errorc.Field("checked", strconv.Itoa(s.Checked)),
+	)
+}
+
+// isFailure checks whether the status code matches any of the given failure criteria.
+// Criteria are: "any" for all broken links, status codes classes like "4xx",
+// status codes like "404", and status labels like "ERR".
+func isFailure(criteria []string, code int) bool {
+	for _, c := range criteria {
+		c = strings.TrimSpace(c)
+
+		switch {
+		case strings.EqualFold(c, failOnAny):
+			if isBroken(code) {
+				return true
+			}
+
+		case isStatusClass(c):
+			if strconv.Itoa(code/100) == c[:1] {
+				return true
+			}
+
+		case strings.EqualFold(c, getStatusLabel(code)):
+			return true
+		}
+	}
+
+	return false
+}
+
+// isValidFailureCriterion checks whether the value is a valid inspector.failOn value.
+func isValidFailureCriterion(c string) bool {
+	c = strings.TrimSpace(c)
+
+	if strings.EqualFold(c, failOnAny) || isStatusClass(c) {
+		return true
+	}
+
+	if code, err := strconv.Atoi(c); err == nil {
+		return code >= 100 && code <= 599
+	}
+
+	for _, label := range statuses {
+		if strings.EqualFold(c, label) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isStatusClass checks whether the value is a status codes class like "4xx".
+func isStatusClass(c string) bool {
+	return len(c) == 3 && c[0] >= '1' && c[0] <= '5' && strings.EqualFold(c[1:], "xx")
+}
+
+// isBroken checks whether the status code represents a broken link.
+func isBroken(code int) bool {
+	return code >= http.StatusBadRequest && !isUnchecked(code)
+}
+
+func containsCode(codes []int, code int) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+
+	return false
+}
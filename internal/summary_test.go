@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummary(t *testing.T) {
+	data := &sync.Map{}
+	for _, l := range []*link{
+		{URL: "link1", code: http.StatusOK},
+		{URL: "link2", code: http.StatusNotFound},
+		{URL: "link3", code: http.StatusNotFound},
+		{URL: "link4", code: http.StatusInternalServerError},
+		{URL: "link5", code: statusError},
+		{URL: "link6", code: statusExternalLink},
+		{URL: "link7", code: http.StatusForbidden},
+		{URL: "link8", code: statusAnchorNotFound},
+	} {
+		data.Store(l.URL, l)
+	}
+
+	tests := []struct {
+		name        string
+		cfg         *inspectorConfig
+		expected    *summary
+		expectedErr string
+	}{
+		{
+			name: "no failure criteria",
+			cfg:  &inspectorConfig{SkipStatusCodes: []int{http.StatusForbidden}},
+			expected: &summary{
+				Checked:  7,
+				Broken:   5,
+				Statuses: map[string]int{"200": 1, "404": 2, "500": 1, "ERR": 1, "EXT": 1, "ANCHOR": 1},
+			},
+		},
+
+		{
+			name: "any",
+			cfg:  &inspectorConfig{FailOn: []string{"any"}},
+			expected: &summary{
+				Checked:  8,
+				Broken:   6,
+				Failed:   6,
+				Statuses: map[string]int{"200": 1, "403": 1, "404": 2, "500": 1, "ERR": 1, "EXT": 1, "ANCHOR": 1},
+			},
+			expectedErr: "inspection failed, failed: 6, threshold: 0, broken: 6, checked: 8",
+		},
+
+		{
+			name: "codes classes and labels",
+			cfg:  &inspectorConfig{FailOn: []string{"5xx", "err", "403"}, SkipStatusCodes: []int{http.StatusNotFound}},
+			expected: &summary{
+				Checked:  6,
+				Broken:   4,
+				Failed:   3,
+				Statuses: map[string]int{"200": 1, "403": 1, "500": 1, "ERR": 1, "EXT": 1, "ANCHOR": 1},
+			},
+			expectedErr: "inspection failed, failed: 3, threshold: 0, broken: 4, checked: 6",
+		},
+
+		{
+			name: "threshold not exceeded",
+			cfg:  &inspectorConfig{FailOn: []string{"4xx"}, FailThreshold: 3},
+			expected: &summary{
+				Checked:  8,
+				Broken:   6,
+				Failed:   3,
+				Statuses: map[string]int{"200": 1, "403": 1, "404": 2, "500": 1, "ERR": 1, "EXT": 1, "ANCHOR": 1},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, newSummary(test.cfg, data))
+
+			err := checkFailure(test.cfg, data)
+			if test.expectedErr != "" {
+				require.ErrorIs(t, err, ErrInspectionFailed)
+				require.EqualError(t, err, test.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestIsValidFailureCriterion(t *testing.T) {
+	for _, v := range []string{"any", "4xx", "5XX", "404", "ERR", "anchor", " ext "} {
+		require.True(t, isValidFailureCriterion(v), v)
+	}
+
+	for _, v := range []string{"", "6xx", "4x", "99", "600", "invalid"} {
+		require.False(t, isValidFailureCriterion(v), v)
+	}
+}
@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+
+	"github.com/ygrebnov/errorc"
+)
+
+// tlsVersions maps inspector.tls.minVersion values to TLS versions.
+// Empty value stands for the Go default minimum version.
+var tlsVersions = map[string]uint16{
+	"":    0,
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// newTLSConfig creates a TLS configuration according to inspector.tls.
+// Nil is returned, if it is not set, so that the transport defaults are used.
+// External configuration does not contain client certificates.
+func newTLSConfig(cfg *inspectorConfig, external bool) (*tls.Config, error) {
+	if cfg.TLS == nil {
+		return nil, nil
+	}
+
+	c := &tls.Config{
+		MinVersion:         tlsVersions[cfg.TLS.MinVersion],
+		InsecureSkipVerify: cfg.TLS.InsecureSkipVerify, //nolint:gosec // explicitly requested, warned on start.
+	}
+
+	if cfg.TLS.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		b, err := os.ReadFile(cfg.TLS.CAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, errorc.With(ErrInvalidCAFile, errorc.Field("path", cfg.TLS.CAFile))
+		}
+
+		c.RootCAs = pool
+	}
+
+	if external {
+		return c, nil
+	}
+
+	for _, cert := range cfg.TLS.Certificates {
+		pair, err := tls.LoadX509KeyPair(cert.CertFile, cert.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Certificates = append(c.Certificates, pair)
+	}
+
+	return c, nil
+}
+
+// isCertificateError checks whether the request error is caused by the server certificate verification failure.
+func isCertificateError(err error) bool {
+	var (
+		certErr      *tls.CertificateVerificationError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+	)
+
+	return errors.As(err, &certErr) ||
+		errors.As(err, &authorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr)
+}
@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCertificate writes a self-signed certificate and its private key into the given directory.
+func writeTestCertificate(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "links client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certFile, keyFile
+}
+
+func TestHTTPClient_TLS(t *testing.T) {
+	s := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	s.TLS = &tls.Config{ClientAuth: tls.RequestClientCert, MaxVersion: tls.VersionTLS12}
+	s.Config.ErrorLog = log.New(io.Discard, "", 0)
+	s.StartTLS()
+	defer s.Close()
+
+	dir := t.TempDir()
+
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(
+		t,
+		os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw}), 0o600),
+	)
+
+	invalidCAFile := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidCAFile, []byte("invalid"), 0o600))
+
+	certFile, keyFile := writeTestCertificate(t, dir)
+
+	tests := []struct {
+		name              string
+		tls               *tlsConfig
+		external          bool
+		expectedCode      int
+		expectedReason    string
+		expectedClientErr error
+	}{
+		{
+			name:           "unknown authority",
+			expectedReason: reasonUnknownAuthority,
+		},
+
+		{
+			name:         "ca file",
+			tls:          &tlsConfig{CAFile: caFile},
+			expectedCode: http.StatusUnauthorized,
+		},
+
+		{
+			name:         "insecure skip verify",
+			tls:          &tlsConfig{InsecureSkipVerify: true},
+			expectedCode: http.StatusUnauthorized,
+		},
+
+		{
+			name: "client certificate",
+			tls: &tlsConfig{
+				CAFile:       caFile,
+				Certificates: []certificateConfig{{CertFile: certFile, KeyFile: keyFile}},
+			},
+			expectedCode: http.StatusOK,
+		},
+
+		{
+			name: "no client certificate for external requests",
+			tls: &tlsConfig{
+				CAFile:       caFile,
+				Certificates: []certificateConfig{{CertFile: certFile, KeyFile: keyFile}},
+			},
+			external:     true,
+			expectedCode: http.StatusUnauthorized,
+		},
+
+		{
+			name:           "min version",
+			tls:            &tlsConfig{CAFile: caFile, MinVersion: "1.3"},
+			expectedReason: reasonTLS,
+		},
+
+		{
+			name:              "invalid ca file",
+			tls:               &tlsConfig{CAFile: invalidCAFile},
+			expectedClientErr: ErrInvalidCAFile,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, err := newHTTPClient(&inspectorConfig{Host: s.URL, TLS: test.tls}, test.external)
+			if test.expectedClientErr != nil {
+				require.ErrorIs(t, err, test.expectedClientErr)
+				return
+			}
+			require.NoError(t, err)
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, s.URL, http.NoBody)
+			require.NoError(t, err)
+
+			resp, err := client.Do(req)
+			if test.expectedReason != "" {
+				require.Equal(t, test.expectedReason, getErrorReason(err))
+				return
+			}
+
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			require.Equal(t, test.expectedCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestIsCertificateError(t *testing.T) {
+	require.True(t, isCertificateError(&tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}))
+	require.True(t, isCertificateError(x509.CertificateInvalidError{Reason: x509.Expired}))
+	require.False(t, isCertificateError(tls.AlertError(70)))
+	require.False(t, isCertificateError(nil))
+}
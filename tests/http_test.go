@@ -12,6 +12,9 @@ func newServer() *httptest.Server {
 <li><a href="nosubsequentlinks">no subsequent links</a>,</li>
 <li><a href="error">error</a>,</li>
 <li><a href="notfound">not found</a>,</li>
+<li><a href="redirect">redirect</a>,</li>
+<li><a href="nosubsequentlinks#missing">missing anchor</a>,</li>
+<li><a href="private">disallowed by robots.txt</a>,</li>
 <li><a href="http://other.host">external link</a>.</li>
 </ul>`)
 	}
@@ -20,14 +23,32 @@ func newServer() *httptest.Server {
 		_, _ = io.WriteString(w, "No links here.")
 	}
 
+	robotsHandler := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "User-agent: *\nDisallow: /private\nSitemap: http://"+r.Host+"/sitemap.xml\n")
+	}
+
+	sitemapHandler := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc>http://`+r.Host+`/</loc></url>
+	<url><loc>http://`+r.Host+`/nosubsequentlinks</loc></url>
+	<url><loc>http://`+r.Host+`/orphan</loc></url>
+</urlset>`)
+	}
+
 	errorHandler := func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 	}
 
 	http.HandleFunc("/", rootHandler)
 	http.HandleFunc("/nosubsequentlinks", nosubsequentlinksHandler)
+	http.HandleFunc("/private", nosubsequentlinksHandler)
+	http.HandleFunc("/robots.txt", robotsHandler)
+	http.HandleFunc("/sitemap.xml", sitemapHandler)
+	http.HandleFunc("/orphan", nosubsequentlinksHandler)
 	http.HandleFunc("/error", errorHandler)
 	http.HandleFunc("/notfound", http.NotFound)
+	http.Handle("/redirect", http.RedirectHandler("/nosubsequentlinks", http.StatusMovedPermanently))
 
 	return httptest.NewServer(http.DefaultServeMux)
 }
@@ -3,7 +3,10 @@ package tests
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -14,6 +17,34 @@ func TestE2E(t *testing.T) {
 	s := newServer()
 	defer s.Close()
 
+	u, err := url.Parse(s.URL)
+	require.NoError(t, err)
+
+	// resolvedURL is resolved into the test server address with --resolve.
+	resolvedURL := "http://links.test:" + u.Port()
+
+	// siteDir is a static site build inspected offline with --dir.
+	siteDir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(siteDir, "docs"), 0o700))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(siteDir, "index.html"),
+		[]byte(`<a href="/about">About</a><a href="/docs">Docs</a><img src="/logo.png">`),
+		0o600,
+	))
+	require.NoError(t, os.WriteFile(filepath.Join(siteDir, "about.html"), []byte(`<a href="/#top">Home</a>`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(siteDir, "docs", "index.html"), []byte(`<a href="guide">Guide</a>`), 0o600))
+
+	urlsFile := filepath.Join(t.TempDir(), "urls.txt")
+	require.NoError(t, os.WriteFile(urlsFile, []byte("# checked URLs\n"+s.URL+"/notfound\n/private\n"), 0o600))
+
+	// sessionCfgFile holds credentials for the inspected host.
+	sessionCfgFile := filepath.Join(t.TempDir(), "session.yaml")
+	require.NoError(t, os.WriteFile(
+		sessionCfgFile,
+		[]byte("inspector:\n    auth:\n        - username: user\n          password: password\n"),
+		0o600,
+	))
+
 	tests := []struct {
 		name          string
 		args          []string
@@ -34,11 +65,21 @@ func TestE2E(t *testing.T) {
 					"    requestTimeout: 30s",
 					"    doNotFollowRedirects: false",
 					"    logExternalLinks: false",
+					"    checkExternalLinks: false",
+					"    externalRequestTimeout: 10s",
+					"    externalConcurrency: 4",
 					"    retryAttempts: 3",
 					"    retryDelay: 2ms",
+					"    retryMaxDelay: 30s",
+					"    retryStatusCodes:",
+					"        - 429",
+					"        - 503",
+					"    ignoreRobotsTxt: false",
+					"    reportExcluded: false",
 					"printer:",
 					"    sortOutput: false",
 					"    displayOccurrences: false",
+					"    displayReferrers: false",
 					"    skipOK: false",
 					"    doNotOpenFileReport: false",
 				}
@@ -55,13 +96,136 @@ func TestE2E(t *testing.T) {
 				fmt.Sprintf("500 - %s/error", s.URL),
 				fmt.Sprintf("404 - %s/notfound", s.URL),
 				fmt.Sprintf("200 - %s/nosubsequentlinks", s.URL),
+				fmt.Sprintf("301 -> 200 - %s/redirect -> %s/nosubsequentlinks", s.URL, s.URL),
+				fmt.Sprintf("ANCHOR - %s/nosubsequentlinks#missing", s.URL),
+				fmt.Sprintf("ROBOTS - %s/private", s.URL),
+			},
+		},
+
+		{
+			name:          "inspect fail on",
+			args:          []string{"inspect", "--host", s.URL, "--fail-on", "5xx,ANCHOR"},
+			expectedError: "inspection failed, failed: 2, threshold: 0, broken: 3, checked: 7",
+		},
+
+		{
+			name: "inspect fail threshold",
+			args: []string{"inspect", "--host", s.URL, "--fail-on", "5xx", "--fail-threshold", "1"},
+			expected: []string{
+				fmt.Sprintf("200 - %s/", s.URL),
+				fmt.Sprintf("500 - %s/error", s.URL),
+				fmt.Sprintf("404 - %s/notfound", s.URL),
+				fmt.Sprintf("200 - %s/nosubsequentlinks", s.URL),
+				fmt.Sprintf("301 -> 200 - %s/redirect -> %s/nosubsequentlinks", s.URL, s.URL),
+				fmt.Sprintf("ANCHOR - %s/nosubsequentlinks#missing", s.URL),
+				fmt.Sprintf("ROBOTS - %s/private", s.URL),
+			},
+		},
+
+		{
+			name: "inspect ignore robots",
+			args: []string{"inspect", "--host", s.URL, "--ignore-robots"},
+			expected: []string{
+				fmt.Sprintf("200 - %s/", s.URL),
+				fmt.Sprintf("500 - %s/error", s.URL),
+				fmt.Sprintf("404 - %s/notfound", s.URL),
+				fmt.Sprintf("200 - %s/nosubsequentlinks", s.URL),
+				fmt.Sprintf("301 -> 200 - %s/redirect -> %s/nosubsequentlinks", s.URL, s.URL),
+				fmt.Sprintf("ANCHOR - %s/nosubsequentlinks#missing", s.URL),
+				fmt.Sprintf("200 - %s/private", s.URL),
+			},
+		},
+
+		{
+			name: "inspect sitemap",
+			args: []string{"inspect", "--host", s.URL, "--sitemap", "auto"},
+			expected: []string{
+				fmt.Sprintf("200 - %s/", s.URL),
+				fmt.Sprintf("500 - %s/error", s.URL),
+				fmt.Sprintf("404 - %s/notfound", s.URL),
+				fmt.Sprintf("200 - %s/nosubsequentlinks", s.URL),
+				fmt.Sprintf("301 -> 200 - %s/redirect -> %s/nosubsequentlinks", s.URL, s.URL),
+				fmt.Sprintf("ANCHOR - %s/nosubsequentlinks#missing", s.URL),
+				fmt.Sprintf("ROBOTS - %s/private", s.URL),
+				fmt.Sprintf("200 - %s/orphan", s.URL),
+				fmt.Sprintf("orphan page - %s/orphan", s.URL),
+			},
+		},
+
+		{
+			name: "inspect resolve",
+			args: []string{"inspect", "--host", resolvedURL, "--resolve", "links.test:" + u.Port() + ":" + u.Hostname()},
+			expected: []string{
+				fmt.Sprintf("200 - %s/", resolvedURL),
+				fmt.Sprintf("500 - %s/error", resolvedURL),
+				fmt.Sprintf("404 - %s/notfound", resolvedURL),
+				fmt.Sprintf("200 - %s/nosubsequentlinks", resolvedURL),
+				fmt.Sprintf("301 -> 200 - %s/redirect -> %s/nosubsequentlinks", resolvedURL, resolvedURL),
+				fmt.Sprintf("ANCHOR - %s/nosubsequentlinks#missing", resolvedURL),
+				fmt.Sprintf("ROBOTS - %s/private", resolvedURL),
+			},
+		},
+
+		{
+			name: "inspect multiple start URLs",
+			args: []string{
+				"inspect",
+				"--url", s.URL + "/nosubsequentlinks",
+				"--url", resolvedURL + "/redirect",
+				"--resolve", "links.test:" + u.Port() + ":" + u.Hostname(),
+			},
+			expected: []string{
+				"site: " + s.URL,
+				fmt.Sprintf("200 - %s/nosubsequentlinks", s.URL),
+				"site: " + resolvedURL,
+				fmt.Sprintf("301 -> 200 - %s/redirect -> %s/nosubsequentlinks", resolvedURL, resolvedURL),
 			},
 		},
 
 		{
 			name:          "inspect no host",
 			args:          []string{"inspect"},
-			expectedError: "Error: required flag(s) \"host\" not set",
+			expectedError: "Error: cannot load configuration: empty host value",
+		},
+
+		{
+			name: "inspect dir",
+			args: []string{"inspect", "--dir", siteDir},
+			expected: []string{
+				"200 - http://localhost/",
+				"200 - http://localhost/about",
+				"301 -> 200 - http://localhost/docs -> http://localhost/docs/",
+				"404 - http://localhost/docs/guide",
+				"404 - http://localhost/logo.png",
+			},
+		},
+
+		{
+			name: "check",
+			args: []string{"check", s.URL + "/", s.URL + "/error", "/nosubsequentlinks#missing"},
+			expected: []string{
+				fmt.Sprintf("200 - %s/", s.URL),
+				fmt.Sprintf("500 - %s/error", s.URL),
+				fmt.Sprintf("200 - %s/nosubsequentlinks", s.URL),
+			},
+		},
+
+		{
+			name:          "check file",
+			args:          []string{"check", "--host", s.URL, "--file", urlsFile, "--fail-on", "4xx"},
+			expectedError: "Error: inspection failed, failed: 1, threshold: 0, broken: 1, checked: 2",
+		},
+
+		{
+			name:          "check session without host",
+			args:          []string{"check", "--config", sessionCfgFile, s.URL + "/"},
+			expectedError: "Error: host is required for checking with inspected host session",
+		},
+
+		{
+			name:          "check no URLs",
+			args:          []string{"check"},
+			expectedError: "Error: no URLs to check",
 		},
 
 		{